@@ -0,0 +1,126 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"mangaparty/db"
+)
+
+// requireAdmin only lets requests through that present the configured admin token
+// as a bearer token. Without a configured token the admin routes do not exist.
+func (s *Server) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.cfg.AdminToken == "" {
+			http.NotFound(w, r)
+			return
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.cfg.AdminToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// handleLargeAttrs lists the works whose representative_attributes exceed the size
+// threshold, largest first. The threshold defaults to ATTRS_WARN_BYTES and can be
+// overridden with ?min_bytes=. The report is paged like the other list endpoints.
+func (s *Server) handleLargeAttrs(w http.ResponseWriter, r *http.Request) {
+	p, err := s.parsePage(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	minBytes := min(s.cfg.AttrsWarnBytes, math.MaxInt32)
+	if v := r.URL.Query().Get("min_bytes"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || n < 0 {
+			http.Error(w, "Invalid min_bytes", http.StatusBadRequest)
+			return
+		}
+		minBytes = int(n)
+	}
+
+	ctx := r.Context()
+	works, err := s.queries.ListLargeWorkAttrs(ctx, db.ListLargeWorkAttrsParams{
+		MinBytes:  int32(minBytes),
+		RowLimit:  p.Limit,
+		RowOffset: p.Offset,
+	})
+	if err != nil {
+		http.Error(w, "Database error: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	total, err := s.queries.CountLargeWorkAttrs(ctx, int32(minBytes))
+	if err != nil {
+		http.Error(w, "Database error: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	setOffsetLinks(w, r, p, total)
+	writeList(w, nonNil(works), pageMeta{Limit: p.Limit, Offset: p.Offset, Total: &total, Truncated: p.truncated(total)})
+}
+
+// validateAttrsBatchSize is how many works handleValidateAttrs holds in memory at once.
+const validateAttrsBatchSize = 500
+
+// attrsViolation is one line of the validate-attrs report.
+type attrsViolation struct {
+	ID     pgtype.UUID `json:"id"`
+	Errors []string    `json:"errors"`
+}
+
+// handleValidateAttrs audits every work's representative_attributes against the
+// current schema. The catalog is walked in id-ordered batches and the result is
+// streamed as NDJSON: one {"id","errors"} line per non-conforming work, flushed
+// after each batch, followed by a final {"checked","invalid"} summary line.
+func (s *Server) handleValidateAttrs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rc := http.NewResponseController(w)
+	enc := json.NewEncoder(w)
+	batchSize := int32(min(validateAttrsBatchSize, s.cfg.MaxResultRows))
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+
+	// The nil UUID sorts before every generated id.
+	after := pgtype.UUID{Valid: true}
+	checked, invalid := 0, 0
+	for {
+		works, err := s.queries.ListWorkAttrsBatch(ctx, db.ListWorkAttrsBatchParams{
+			AfterID:  after,
+			RowLimit: batchSize,
+		})
+		if err != nil {
+			// Earlier batches may already be on the wire, so report the failure in-band.
+			log.Printf("validate-attrs: %v", err)
+			enc.Encode(map[string]string{"error": "Database error: " + err.Error()})
+			return
+		}
+
+		for _, work := range works {
+			if errs := validateAttrs(work.RepresentativeAttributes); len(errs) > 0 {
+				invalid++
+				enc.Encode(attrsViolation{ID: work.ID, Errors: errs})
+			}
+		}
+		checked += len(works)
+		rc.Flush()
+
+		if len(works) < int(batchSize) {
+			break
+		}
+		after = works[len(works)-1].ID
+	}
+
+	enc.Encode(map[string]int{"checked": checked, "invalid": invalid})
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// checkAttrsSize flags representative_attributes values that are large enough to
+// hurt JSONB performance. Oversized values are always logged and counted; they are
+// only rejected when ATTRS_REJECT_OVERSIZE is set. size must come from AttrsTextSize,
+// so that it is the number the large-attrs report would show for the stored value.
+func (s *Server) checkAttrsSize(size int) error {
+	if size <= s.cfg.AttrsWarnBytes {
+		return nil
+	}
+	attrsOversizeTotal.Add(1)
+	log.Printf("WARN: representative_attributes is %d bytes (threshold %d)", size, s.cfg.AttrsWarnBytes)
+	if s.cfg.AttrsRejectOversize {
+		return fmt.Errorf("representative_attributes is %d bytes, the limit is %d", size, s.cfg.AttrsWarnBytes)
+	}
+	return nil
+}
+
+// representativeAttrKeys is the expected shape of mp_work.representative_attributes:
+// the values cached from the canonical expression, each a list of strings like the
+// mp_expression column it mirrors. It is only checked by the validate-attrs audit;
+// writes are not rejected for other keys.
+var representativeAttrKeys = map[string]bool{
+	"language":           true,
+	"musical_key":        true,
+	"cartographic_scale": true,
+}
+
+// validateAttrs checks a representative_attributes value against the schema and
+// returns one message per problem. An absent or null value is valid.
+func validateAttrs(raw []byte) []string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return []string{"must be a JSON object"}
+	}
+
+	var errs []string
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if !representativeAttrKeys[k] {
+			errs = append(errs, fmt.Sprintf("%s: unknown attribute", k))
+			continue
+		}
+		var values []string
+		if err := json.Unmarshal(obj[k], &values); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: must be an array of strings", k))
+			continue
+		}
+		for i, v := range values {
+			if strings.TrimSpace(v) == "" {
+				errs = append(errs, fmt.Sprintf("%s[%d]: must not be empty", k, i))
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the tunables read from the environment at startup.
+type Config struct {
+	// AdminToken guards the /api/admin routes. Admin routes are disabled when it is empty.
+	AdminToken string
+
+	// AttrsWarnBytes is the size of a work's representative_attributes (as JSON text)
+	// above which the value is logged and counted as oversized. Postgres starts
+	// TOASTing rows at roughly 2kB, which is where JSONB access gets noticeably slower.
+	AttrsWarnBytes int
+	// AttrsRejectOversize turns the warning into a hard error on write.
+	AttrsRejectOversize bool
+
+	// MaxResultRows is the hard cap on rows any single multi-row query may return.
+	// Hitting it is signalled to clients rather than silently dropping rows.
+	MaxResultRows int
+
+	// NormalizeValues enables write-time normalization (trim + lowercase) of the
+	// category, language and profession arrays. Off by default so that consumers
+	// relying on the submitted casing are not surprised.
+	NormalizeValues bool
+	// NormalizeExceptions are values kept in exactly this spelling when normalizing,
+	// matched case-insensitively (e.g. "BL", "CLAMP").
+	NormalizeExceptions []string
+
+	// EmptyArrays is how an empty or absent array field is stored on write: "empty"
+	// stores '{}', which is what the create forms already produced, and "null" stores
+	// SQL NULL. It covers every array column the API writes, but existing rows are not
+	// rewritten, so older rows may still hold the other form.
+	EmptyArrays string
+
+	// StatsCacheTTL is how long aggregate statistics responses are served from memory.
+	StatsCacheTTL time.Duration
+
+	// DraftTTL is how long an auto-saved form draft is kept after its last save.
+	DraftTTL time.Duration
+	// ReaperInterval is how often the background reaper deletes expired rows.
+	ReaperInterval time.Duration
+}
+
+func loadConfig() Config {
+	return Config{
+		AdminToken:          os.Getenv("ADMIN_TOKEN"),
+		AttrsWarnBytes:      envInt("ATTRS_WARN_BYTES", 2000),
+		AttrsRejectOversize: envBool("ATTRS_REJECT_OVERSIZE", false),
+		MaxResultRows:       max(envInt("MAX_RESULT_ROWS", 1000), 1),
+		NormalizeValues:     envBool("NORMALIZE_VALUES", false),
+		NormalizeExceptions: envList("NORMALIZE_EXCEPTIONS"),
+		EmptyArrays:         envChoice("EMPTY_ARRAYS", emptyArraysEmpty, emptyArraysEmpty, emptyArraysNull),
+		StatsCacheTTL:       envDuration("STATS_CACHE_TTL", time.Minute),
+		DraftTTL:            envDuration("DRAFT_TTL", 24*time.Hour),
+		ReaperInterval:      envDuration("REAPER_INTERVAL", 10*time.Minute),
+	}
+}
+
+// Values of Config.EmptyArrays.
+const (
+	emptyArraysNull  = "null"
+	emptyArraysEmpty = "empty"
+)
+
+// envInt reads an integer environment variable, falling back to def when unset or invalid.
+func envInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("Ignoring invalid %s=%q: %v", key, v, err)
+		return def
+	}
+	return n
+}
+
+// envBool reads a boolean environment variable, falling back to def when unset or invalid.
+func envBool(key string, def bool) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Printf("Ignoring invalid %s=%q: %v", key, v, err)
+		return def
+	}
+	return b
+}
+
+// envDuration reads a time.ParseDuration-style environment variable, falling back to
+// def when unset, invalid or not positive.
+func envDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Printf("Ignoring invalid %s=%q", key, v)
+		return def
+	}
+	return d
+}
+
+// envChoice reads an environment variable that must be one of choices, falling back
+// to def when unset or not recognised.
+func envChoice(key, def string, choices ...string) string {
+	v := strings.ToLower(strings.TrimSpace(os.Getenv(key)))
+	if v == "" {
+		return def
+	}
+	for _, c := range choices {
+		if v == c {
+			return v
+		}
+	}
+	log.Printf("Ignoring invalid %s=%q: want one of %s", key, v, strings.Join(choices, ", "))
+	return def
+}
+
+// envList reads a comma-separated environment variable, dropping empty entries.
+func envList(key string) []string {
+	var list []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"mangaparty/db"
+)
+
+// CreateContributionRequest defines the JSON payload for crediting an agent on a work.
+type CreateContributionRequest struct {
+	AgentID string `json:"agent_id"`
+	Role    string `json:"role"`
+}
+
+// Contributor is one credited agent within a ContributorGroup.
+type Contributor struct {
+	ContributionID pgtype.UUID     `json:"contribution_id"`
+	AgentID        pgtype.UUID     `json:"agent_id"`
+	EntityType     db.MpEntityType `json:"entity_type"`
+}
+
+// ContributorGroup collects the contributors sharing a role. Priority is null for
+// roles that have no entry in mp_contributor_role.
+type ContributorGroup struct {
+	Role         string        `json:"role"`
+	Label        string        `json:"label"`
+	Priority     pgtype.Int4   `json:"priority"`
+	Contributors []Contributor `json:"contributors"`
+}
+
+// workIDParam parses the {id} path segment of a work route and checks the work exists.
+func (s *Server) workIDParam(w http.ResponseWriter, r *http.Request) (pgtype.UUID, bool) {
+	workID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return pgtype.UUID{}, false
+	}
+	id := pgtype.UUID{Bytes: workID, Valid: true}
+	if _, err := s.queries.GetWork(r.Context(), id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "Work not found", http.StatusNotFound)
+			return pgtype.UUID{}, false
+		}
+		http.Error(w, "Database error: "+err.Error(), http.StatusInternalServerError)
+		return pgtype.UUID{}, false
+	}
+	return id, true
+}
+
+// handleListContributors returns a work's credits grouped by role. Groups are ordered
+// by the role priority in mp_contributor_role (e.g. writer before artist), and roles
+// without metadata follow alphabetically.
+func (s *Server) handleListContributors(w http.ResponseWriter, r *http.Request) {
+	workID, ok := s.workIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	rows, err := s.queries.ListWorkContributors(r.Context(), db.ListWorkContributorsParams{
+		WorkID: workID,
+		Limit:  s.rowLimit(),
+	})
+	if err != nil {
+		http.Error(w, "Failed to fetch contributors: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rows, truncated := capRows(w, rows, s.cfg.MaxResultRows)
+
+	// Rows arrive in display order, so grouping only has to watch for role changes.
+	groups := []ContributorGroup{}
+	for _, row := range rows {
+		role := strings.ToLower(row.Role)
+		if n := len(groups); n == 0 || groups[n-1].Role != role {
+			groups = append(groups, ContributorGroup{
+				Role:     role,
+				Label:    row.RoleLabel,
+				Priority: row.RolePriority,
+			})
+		}
+		g := &groups[len(groups)-1]
+		g.Contributors = append(g.Contributors, Contributor{
+			ContributionID: row.ID,
+			AgentID:        row.AgentID,
+			EntityType:     row.EntityType,
+		})
+	}
+
+	writeList(w, groups, pageMeta{Limit: int32(s.cfg.MaxResultRows), Truncated: truncated})
+}
+
+// handleCreateContribution credits an agent with a role on a work.
+func (s *Server) handleCreateContribution(w http.ResponseWriter, r *http.Request) {
+	workID, ok := s.workIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req CreateContributionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	agentID, err := uuid.Parse(req.AgentID)
+	if err != nil {
+		http.Error(w, "Invalid agent_id", http.StatusBadRequest)
+		return
+	}
+	role := strings.TrimSpace(req.Role)
+	if role == "" {
+		http.Error(w, "role is required", http.StatusBadRequest)
+		return
+	}
+
+	res, err := s.queries.CreateContribution(r.Context(), db.CreateContributionParams{
+		WorkID:  workID,
+		AgentID: pgtype.UUID{Bytes: agentID, Valid: true},
+		Role:    role,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23503": // foreign_key_violation: the agent does not exist
+				http.Error(w, "Agent not found", http.StatusNotFound)
+				return
+			case "23505": // unique_violation
+				http.Error(w, "Agent is already credited with this role", http.StatusConflict)
+				return
+			}
+		}
+		http.Error(w, "Failed to create contribution: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{"id": res.ID, "status": "created"})
+}
@@ -6,6 +6,7 @@ package db
 
 import (
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 
 	"github.com/jackc/pgx/v5/pgtype"
@@ -174,6 +175,24 @@ type MpContentRating struct {
 	RatingValue pgtype.Text `json:"rating_value"`
 }
 
+// Credits an Agent with a role on a Work (a typed refinement of MP_R5).
+type MpContribution struct {
+	ID        pgtype.UUID        `json:"id"`
+	WorkID    pgtype.UUID        `json:"work_id"`
+	AgentID   pgtype.UUID        `json:"agent_id"`
+	Role      string             `json:"role"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+// Role metadata for credit lists. Edit to change the display order of roles.
+type MpContributorRole struct {
+	// Lowercase role key, matched case-insensitively against mp_contribution.role
+	Role  string `json:"role"`
+	Label string `json:"label"`
+	// Lower values are listed first; roles without metadata sort last, alphabetically
+	Priority int32 `json:"priority"`
+}
+
 // Custom superclass for digital assets.
 type MpDigitalResource struct {
 	ID                 pgtype.UUID `json:"id"`
@@ -181,6 +200,16 @@ type MpDigitalResource struct {
 	AccessRestrictions []string    `json:"access_restrictions"`
 }
 
+// Auto-saved state of unsubmitted create forms, keyed by browser session. Expired rows are deleted by the reaper.
+type MpDraft struct {
+	SessionID string `json:"session_id"`
+	// Which form the draft belongs to, e.g. person, work
+	Kind      string             `json:"kind"`
+	Data      []byte             `json:"data"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
 // MP-E3 (LRM-E3): A distinct combination of signs conveying content.
 type MpExpression struct {
 	ID pgtype.UUID `json:"id"`
@@ -342,5 +371,5 @@ type MpWork struct {
 	// e.g. termination intention, creative domain
 	Category []string `json:"category"`
 	// Stores cached values from the canonical expression (Key, Language, Scale)
-	RepresentativeAttributes []byte `json:"representative_attributes"`
+	RepresentativeAttributes json.RawMessage `json:"representative_attributes"`
 }
@@ -11,7 +11,21 @@ import (
 )
 
 type Querier interface {
+	// Size of a representative_attributes value measured exactly as ListLargeWorkAttrs does;
+	// 0 for NULL.
+	AttrsTextSize(ctx context.Context, attrs []byte) (int32, error)
+	// Number of agents per language, most common first.
+	CountAgentsByLanguage(ctx context.Context, limit int32) ([]CountAgentsByLanguageRow, error)
+	CountLargeWorkAttrs(ctx context.Context, minBytes int32) (int64, error)
+	CountPeople(ctx context.Context) (int64, error)
+	CountPeopleByFieldOfActivity(ctx context.Context, fieldOfActivity string) (int64, error)
+	CountPeopleByLanguage(ctx context.Context, arg CountPeopleByLanguageParams) (int64, error)
+	CountPeopleByProfession(ctx context.Context, arg CountPeopleByProfessionParams) (int64, error)
+	CountWorks(ctx context.Context) (int64, error)
+	CountWorksByCategory(ctx context.Context, category string) (int64, error)
+	CountWorksByContributorCount(ctx context.Context, arg CountWorksByContributorCountParams) (int64, error)
 	CreateAgent(ctx context.Context, arg CreateAgentParams) error
+	CreateContribution(ctx context.Context, arg CreateContributionParams) (CreateContributionRow, error)
 	CreateExpression(ctx context.Context, arg CreateExpressionParams) error
 	CreateItem(ctx context.Context, arg CreateItemParams) error
 	CreateManifestation(ctx context.Context, arg CreateManifestationParams) error
@@ -19,20 +33,60 @@ type Querier interface {
 	CreateRelationship(ctx context.Context, arg CreateRelationshipParams) (pgtype.UUID, error)
 	CreateRes(ctx context.Context, arg CreateResParams) (CreateResRow, error)
 	CreateWork(ctx context.Context, arg CreateWorkParams) error
+	DeleteDraft(ctx context.Context, arg DeleteDraftParams) error
+	DeleteExpiredDrafts(ctx context.Context) (int64, error)
+	GetDraft(ctx context.Context, arg GetDraftParams) (GetDraftRow, error)
 	GetExpression(ctx context.Context, id pgtype.UUID) (GetExpressionRow, error)
 	GetItem(ctx context.Context, id pgtype.UUID) (GetItemRow, error)
 	GetManifestation(ctx context.Context, id pgtype.UUID) (GetManifestationRow, error)
 	// Returns a fully hydrated Person by joining the inheritance tables
 	GetPerson(ctx context.Context, id pgtype.UUID) (GetPersonRow, error)
+	// Same as GetPerson, but locks the person's root row until the transaction ends.
+	GetPersonForUpdate(ctx context.Context, id pgtype.UUID) (GetPersonForUpdateRow, error)
 	GetWork(ctx context.Context, id pgtype.UUID) (GetWorkRow, error)
 	// Demonstrates graph traversal: Find all works created by a specific person
-	GetWorksByCreator(ctx context.Context, targetID pgtype.UUID) ([]GetWorksByCreatorRow, error)
-	ListExpressions(ctx context.Context) ([]ListExpressionsRow, error)
-	ListItems(ctx context.Context) ([]ListItemsRow, error)
-	ListManifestations(ctx context.Context) ([]ListManifestationsRow, error)
-	ListPeople(ctx context.Context) ([]ListPeopleRow, error)
-	ListRes(ctx context.Context) ([]MpRe, error)
-	ListWorks(ctx context.Context) ([]ListWorksRow, error)
+	GetWorksByCreator(ctx context.Context, arg GetWorksByCreatorParams) ([]GetWorksByCreatorRow, error)
+	ListExpressions(ctx context.Context, limit int32) ([]ListExpressionsRow, error)
+	ListItems(ctx context.Context, limit int32) ([]ListItemsRow, error)
+	// Finds works whose representative_attributes exceed the given size, largest first.
+	// attrs_bytes is the JSON text size, stored_bytes the (possibly compressed) on-disk size.
+	ListLargeWorkAttrs(ctx context.Context, arg ListLargeWorkAttrsParams) ([]ListLargeWorkAttrsRow, error)
+	ListManifestations(ctx context.Context, limit int32) ([]ListManifestationsRow, error)
+	ListPeople(ctx context.Context, limit int32) ([]ListPeopleRow, error)
+	ListPeopleByFieldOfActivity(ctx context.Context, arg ListPeopleByFieldOfActivityParams) ([]ListPeopleByFieldOfActivityRow, error)
+	// Optional extra filter: field_of_activity.
+	ListPeopleByLanguage(ctx context.Context, arg ListPeopleByLanguageParams) ([]ListPeopleByLanguageRow, error)
+	// Optional extra filters: language, field_of_activity.
+	ListPeopleByProfession(ctx context.Context, arg ListPeopleByProfessionParams) ([]ListPeopleByProfessionRow, error)
+	// Unfiltered. Filters go through the ListPeopleBy* variants, each led by one required
+	// filter so that its mp_lower_array index stays usable under a generic plan.
+	ListPeoplePage(ctx context.Context, arg ListPeoplePageParams) ([]ListPeoplePageRow, error)
+	ListRes(ctx context.Context, limit int32) ([]MpRe, error)
+	// Walks mp_work in id order for batch processing; pass the last id of the previous batch.
+	ListWorkAttrsBatch(ctx context.Context, arg ListWorkAttrsBatchParams) ([]ListWorkAttrsBatchRow, error)
+	// Credits for a work in display order: by role priority from mp_contributor_role,
+	// then alphabetically by role for roles without metadata.
+	ListWorkContributors(ctx context.Context, arg ListWorkContributorsParams) ([]ListWorkContributorsRow, error)
+	ListWorks(ctx context.Context, limit int32) ([]ListWorksRow, error)
+	// Keyset pagination: the page of works strictly after the given (created_at, id) position.
+	ListWorksAfter(ctx context.Context, arg ListWorksAfterParams) ([]ListWorksAfterRow, error)
+	ListWorksAfterByCategory(ctx context.Context, arg ListWorksAfterByCategoryParams) ([]ListWorksAfterByCategoryRow, error)
+	// Works credited to between min_contributors and max_contributors distinct agents
+	// (either bound optional). Works without contributions count as zero.
+	ListWorksByContributorCount(ctx context.Context, arg ListWorksByContributorCountParams) ([]ListWorksByContributorCountRow, error)
+	// Unfiltered; see ListWorksPageByCategory.
+	ListWorksPage(ctx context.Context, arg ListWorksPageParams) ([]ListWorksPageRow, error)
+	// A separate query rather than an optional filter so that the category index stays
+	// usable under a generic plan.
+	ListWorksPageByCategory(ctx context.Context, arg ListWorksPageByCategoryParams) ([]ListWorksPageByCategoryRow, error)
+	// Fetches any mix of resources in one round trip. Subtype columns are NULL unless the
+	// resource is of that subtype.
+	ResolveResources(ctx context.Context, ids []pgtype.UUID) ([]ResolveResourcesRow, error)
+	UpdateAgent(ctx context.Context, arg UpdateAgentParams) error
+	UpdatePerson(ctx context.Context, arg UpdatePersonParams) error
+	// Also bumps updated_at through the update_mp_res_modtime trigger.
+	UpdateResNote(ctx context.Context, arg UpdateResNoteParams) error
+	UpsertDraft(ctx context.Context, arg UpsertDraftParams) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -7,10 +7,190 @@ package db
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const attrsTextSize = `-- name: AttrsTextSize :one
+SELECT coalesce(octet_length($1::jsonb::text), 0)::int AS size
+`
+
+// Size of a representative_attributes value measured exactly as ListLargeWorkAttrs does;
+// 0 for NULL.
+func (q *Queries) AttrsTextSize(ctx context.Context, attrs []byte) (int32, error) {
+	row := q.db.QueryRow(ctx, attrsTextSize, attrs)
+	var size int32
+	err := row.Scan(&size)
+	return size, err
+}
+
+const countAgentsByLanguage = `-- name: CountAgentsByLanguage :many
+SELECT lang::text AS language, count(DISTINCT a.id) AS agent_count
+FROM mp_agent a, unnest(a.language) AS lang
+GROUP BY lang
+ORDER BY agent_count DESC, lang
+LIMIT $1
+`
+
+type CountAgentsByLanguageRow struct {
+	Language   string `json:"language"`
+	AgentCount int64  `json:"agent_count"`
+}
+
+// Number of agents per language, most common first.
+func (q *Queries) CountAgentsByLanguage(ctx context.Context, limit int32) ([]CountAgentsByLanguageRow, error) {
+	rows, err := q.db.Query(ctx, countAgentsByLanguage, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountAgentsByLanguageRow
+	for rows.Next() {
+		var i CountAgentsByLanguageRow
+		if err := rows.Scan(&i.Language, &i.AgentCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countLargeWorkAttrs = `-- name: CountLargeWorkAttrs :one
+SELECT count(*)
+FROM mp_work w
+WHERE octet_length(w.representative_attributes::text) > $1::int
+`
+
+func (q *Queries) CountLargeWorkAttrs(ctx context.Context, minBytes int32) (int64, error) {
+	row := q.db.QueryRow(ctx, countLargeWorkAttrs, minBytes)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPeople = `-- name: CountPeople :one
+SELECT count(*)
+FROM mp_person
+`
+
+func (q *Queries) CountPeople(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countPeople)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPeopleByFieldOfActivity = `-- name: CountPeopleByFieldOfActivity :one
+SELECT count(*)
+FROM mp_agent a
+JOIN mp_person p ON a.id = p.id
+WHERE mp_lower_array(a.field_of_activity) @> ARRAY[lower($1::text)]
+`
+
+func (q *Queries) CountPeopleByFieldOfActivity(ctx context.Context, fieldOfActivity string) (int64, error) {
+	row := q.db.QueryRow(ctx, countPeopleByFieldOfActivity, fieldOfActivity)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPeopleByLanguage = `-- name: CountPeopleByLanguage :one
+SELECT count(*)
+FROM mp_agent a
+JOIN mp_person p ON a.id = p.id
+WHERE mp_lower_array(a.language) @> ARRAY[lower($1::text)]
+AND ($2::text IS NULL OR mp_lower_array(a.field_of_activity) @> ARRAY[lower($2::text)])
+`
+
+type CountPeopleByLanguageParams struct {
+	Language        string      `json:"language"`
+	FieldOfActivity pgtype.Text `json:"field_of_activity"`
+}
+
+func (q *Queries) CountPeopleByLanguage(ctx context.Context, arg CountPeopleByLanguageParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countPeopleByLanguage, arg.Language, arg.FieldOfActivity)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPeopleByProfession = `-- name: CountPeopleByProfession :one
+SELECT count(*)
+FROM mp_agent a
+JOIN mp_person p ON a.id = p.id
+WHERE mp_lower_array(p.profession) @> ARRAY[lower($1::text)]
+AND ($2::text IS NULL OR mp_lower_array(a.language) @> ARRAY[lower($2::text)])
+AND ($3::text IS NULL OR mp_lower_array(a.field_of_activity) @> ARRAY[lower($3::text)])
+`
+
+type CountPeopleByProfessionParams struct {
+	Profession      string      `json:"profession"`
+	Language        pgtype.Text `json:"language"`
+	FieldOfActivity pgtype.Text `json:"field_of_activity"`
+}
+
+func (q *Queries) CountPeopleByProfession(ctx context.Context, arg CountPeopleByProfessionParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countPeopleByProfession, arg.Profession, arg.Language, arg.FieldOfActivity)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countWorks = `-- name: CountWorks :one
+SELECT count(*)
+FROM mp_work
+`
+
+func (q *Queries) CountWorks(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countWorks)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countWorksByCategory = `-- name: CountWorksByCategory :one
+SELECT count(*)
+FROM mp_work w
+WHERE mp_lower_array(w.category) @> ARRAY[lower($1::text)]
+`
+
+func (q *Queries) CountWorksByCategory(ctx context.Context, category string) (int64, error) {
+	row := q.db.QueryRow(ctx, countWorksByCategory, category)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countWorksByContributorCount = `-- name: CountWorksByContributorCount :one
+SELECT count(*)
+FROM (
+    SELECT w.id
+    FROM mp_work w
+    LEFT JOIN mp_contribution c ON c.work_id = w.id
+    WHERE ($1::text IS NULL OR mp_lower_array(w.category) @> ARRAY[lower($1::text)])
+    GROUP BY w.id
+    HAVING ($2::int IS NULL OR count(DISTINCT c.agent_id) >= $2::int)
+    AND ($3::int IS NULL OR count(DISTINCT c.agent_id) <= $3::int)
+) matched
+`
+
+type CountWorksByContributorCountParams struct {
+	Category        pgtype.Text `json:"category"`
+	MinContributors pgtype.Int4 `json:"min_contributors"`
+	MaxContributors pgtype.Int4 `json:"max_contributors"`
+}
+
+func (q *Queries) CountWorksByContributorCount(ctx context.Context, arg CountWorksByContributorCountParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countWorksByContributorCount, arg.Category, arg.MinContributors, arg.MaxContributors)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createAgent = `-- name: CreateAgent :exec
 INSERT INTO mp_agent (id, contact_info, field_of_activity, language)
 VALUES ($1, $2, $3, $4)
@@ -33,6 +213,30 @@ func (q *Queries) CreateAgent(ctx context.Context, arg CreateAgentParams) error
 	return err
 }
 
+const createContribution = `-- name: CreateContribution :one
+INSERT INTO mp_contribution (work_id, agent_id, role)
+VALUES ($1, $2, $3)
+RETURNING id, created_at
+`
+
+type CreateContributionParams struct {
+	WorkID  pgtype.UUID `json:"work_id"`
+	AgentID pgtype.UUID `json:"agent_id"`
+	Role    string      `json:"role"`
+}
+
+type CreateContributionRow struct {
+	ID        pgtype.UUID        `json:"id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) CreateContribution(ctx context.Context, arg CreateContributionParams) (CreateContributionRow, error) {
+	row := q.db.QueryRow(ctx, createContribution, arg.WorkID, arg.AgentID, arg.Role)
+	var i CreateContributionRow
+	err := row.Scan(&i.ID, &i.CreatedAt)
+	return i, err
+}
+
 const createExpression = `-- name: CreateExpression :exec
 INSERT INTO mp_expression (id, category, extent, intended_audience, use_rights, cartographic_scale, language, musical_key, medium_of_performance)
 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
@@ -178,9 +382,9 @@ VALUES ($1, $2, $3)
 `
 
 type CreateWorkParams struct {
-	ID                       pgtype.UUID `json:"id"`
-	Category                 []string    `json:"category"`
-	RepresentativeAttributes []byte      `json:"representative_attributes"`
+	ID                       pgtype.UUID     `json:"id"`
+	Category                 []string        `json:"category"`
+	RepresentativeAttributes json.RawMessage `json:"representative_attributes"`
 }
 
 func (q *Queries) CreateWork(ctx context.Context, arg CreateWorkParams) error {
@@ -188,6 +392,58 @@ func (q *Queries) CreateWork(ctx context.Context, arg CreateWorkParams) error {
 	return err
 }
 
+const deleteDraft = `-- name: DeleteDraft :exec
+DELETE FROM mp_draft
+WHERE session_id = $1 AND kind = $2
+`
+
+type DeleteDraftParams struct {
+	SessionID string `json:"session_id"`
+	Kind      string `json:"kind"`
+}
+
+func (q *Queries) DeleteDraft(ctx context.Context, arg DeleteDraftParams) error {
+	_, err := q.db.Exec(ctx, deleteDraft, arg.SessionID, arg.Kind)
+	return err
+}
+
+const deleteExpiredDrafts = `-- name: DeleteExpiredDrafts :execrows
+DELETE FROM mp_draft
+WHERE expires_at <= now()
+`
+
+func (q *Queries) DeleteExpiredDrafts(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredDrafts)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getDraft = `-- name: GetDraft :one
+SELECT data, updated_at, expires_at
+FROM mp_draft
+WHERE session_id = $1 AND kind = $2 AND expires_at > now()
+`
+
+type GetDraftParams struct {
+	SessionID string `json:"session_id"`
+	Kind      string `json:"kind"`
+}
+
+type GetDraftRow struct {
+	Data      []byte             `json:"data"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) GetDraft(ctx context.Context, arg GetDraftParams) (GetDraftRow, error) {
+	row := q.db.QueryRow(ctx, getDraft, arg.SessionID, arg.Kind)
+	var i GetDraftRow
+	err := row.Scan(&i.Data, &i.UpdatedAt, &i.ExpiresAt)
+	return i, err
+}
+
 const getExpression = `-- name: GetExpression :one
 SELECT r.id, r.entity_type, r.note, r.created_at, e.category, e.extent, e.intended_audience, e.use_rights, e.cartographic_scale, e.language, e.musical_key, e.medium_of_performance
 FROM mp_res r
@@ -339,6 +595,48 @@ func (q *Queries) GetPerson(ctx context.Context, id pgtype.UUID) (GetPersonRow,
 	return i, err
 }
 
+const getPersonForUpdate = `-- name: GetPersonForUpdate :one
+SELECT 
+    r.id, r.entity_type, r.note, r.created_at, r.updated_at,
+    a.contact_info, a.field_of_activity, a.language,
+    p.profession
+FROM mp_res r
+JOIN mp_agent a ON r.id = a.id
+JOIN mp_person p ON a.id = p.id
+WHERE r.id = $1
+FOR UPDATE OF r
+`
+
+type GetPersonForUpdateRow struct {
+	ID              pgtype.UUID        `json:"id"`
+	EntityType      MpEntityType       `json:"entity_type"`
+	Note            []string           `json:"note"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	ContactInfo     []string           `json:"contact_info"`
+	FieldOfActivity []string           `json:"field_of_activity"`
+	Language        []string           `json:"language"`
+	Profession      []string           `json:"profession"`
+}
+
+// Same as GetPerson, but locks the person's root row until the transaction ends.
+func (q *Queries) GetPersonForUpdate(ctx context.Context, id pgtype.UUID) (GetPersonForUpdateRow, error) {
+	row := q.db.QueryRow(ctx, getPersonForUpdate, id)
+	var i GetPersonForUpdateRow
+	err := row.Scan(
+		&i.ID,
+		&i.EntityType,
+		&i.Note,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ContactInfo,
+		&i.FieldOfActivity,
+		&i.Language,
+		&i.Profession,
+	)
+	return i, err
+}
+
 const getWork = `-- name: GetWork :one
 SELECT r.id, r.entity_type, r.note, r.created_at, w.category, w.representative_attributes
 FROM mp_res r
@@ -352,7 +650,7 @@ type GetWorkRow struct {
 	Note                     []string           `json:"note"`
 	CreatedAt                pgtype.Timestamptz `json:"created_at"`
 	Category                 []string           `json:"category"`
-	RepresentativeAttributes []byte             `json:"representative_attributes"`
+	RepresentativeAttributes json.RawMessage    `json:"representative_attributes"`
 }
 
 func (q *Queries) GetWork(ctx context.Context, id pgtype.UUID) (GetWorkRow, error) {
@@ -376,21 +674,27 @@ FROM mp_relationship rel
 JOIN mp_res r ON rel.source_id = r.id
 JOIN mp_work w ON r.id = w.id
 WHERE rel.target_id = $1 -- The agent's ID
-AND rel.rel_type = 'MP_R5'
+AND rel.rel_type = 'MP_R5' -- 'Work was created by Agent'
+LIMIT $2
 `
 
+type GetWorksByCreatorParams struct {
+	TargetID pgtype.UUID `json:"target_id"`
+	Limit    int32       `json:"limit"`
+}
+
 type GetWorksByCreatorRow struct {
 	ID                       pgtype.UUID        `json:"id"`
 	EntityType               MpEntityType       `json:"entity_type"`
 	Note                     []string           `json:"note"`
 	CreatedAt                pgtype.Timestamptz `json:"created_at"`
 	Category                 []string           `json:"category"`
-	RepresentativeAttributes []byte             `json:"representative_attributes"`
+	RepresentativeAttributes json.RawMessage    `json:"representative_attributes"`
 }
 
 // Demonstrates graph traversal: Find all works created by a specific person
-func (q *Queries) GetWorksByCreator(ctx context.Context, targetID pgtype.UUID) ([]GetWorksByCreatorRow, error) {
-	rows, err := q.db.Query(ctx, getWorksByCreator, targetID)
+func (q *Queries) GetWorksByCreator(ctx context.Context, arg GetWorksByCreatorParams) ([]GetWorksByCreatorRow, error) {
+	rows, err := q.db.Query(ctx, getWorksByCreator, arg.TargetID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -421,6 +725,7 @@ SELECT r.id, r.entity_type, r.note, r.created_at, e.category, e.extent, e.intend
 FROM mp_res r
 JOIN mp_expression e ON r.id = e.id
 ORDER BY r.created_at DESC
+LIMIT $1
 `
 
 type ListExpressionsRow struct {
@@ -438,8 +743,8 @@ type ListExpressionsRow struct {
 	MediumOfPerformance []string           `json:"medium_of_performance"`
 }
 
-func (q *Queries) ListExpressions(ctx context.Context) ([]ListExpressionsRow, error) {
-	rows, err := q.db.Query(ctx, listExpressions)
+func (q *Queries) ListExpressions(ctx context.Context, limit int32) ([]ListExpressionsRow, error) {
+	rows, err := q.db.Query(ctx, listExpressions, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -476,6 +781,7 @@ SELECT r.id, r.entity_type, r.note, r.created_at, i.location, i.use_rights
 FROM mp_res r
 JOIN mp_item i ON r.id = i.id
 ORDER BY r.created_at DESC
+LIMIT $1
 `
 
 type ListItemsRow struct {
@@ -487,8 +793,8 @@ type ListItemsRow struct {
 	UseRights  []string           `json:"use_rights"`
 }
 
-func (q *Queries) ListItems(ctx context.Context) ([]ListItemsRow, error) {
-	rows, err := q.db.Query(ctx, listItems)
+func (q *Queries) ListItems(ctx context.Context, limit int32) ([]ListItemsRow, error) {
+	rows, err := q.db.Query(ctx, listItems, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -514,11 +820,64 @@ func (q *Queries) ListItems(ctx context.Context) ([]ListItemsRow, error) {
 	return items, nil
 }
 
+const listLargeWorkAttrs = `-- name: ListLargeWorkAttrs :many
+SELECT
+    r.id, r.created_at,
+    octet_length(w.representative_attributes::text)::int AS attrs_bytes,
+    pg_column_size(w.representative_attributes) AS stored_bytes
+FROM mp_res r
+JOIN mp_work w ON r.id = w.id
+WHERE octet_length(w.representative_attributes::text) > $1::int
+ORDER BY attrs_bytes DESC, r.id
+LIMIT $2 OFFSET $3
+`
+
+type ListLargeWorkAttrsParams struct {
+	MinBytes  int32 `json:"min_bytes"`
+	RowLimit  int32 `json:"row_limit"`
+	RowOffset int32 `json:"row_offset"`
+}
+
+type ListLargeWorkAttrsRow struct {
+	ID          pgtype.UUID        `json:"id"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	AttrsBytes  int32              `json:"attrs_bytes"`
+	StoredBytes int32              `json:"stored_bytes"`
+}
+
+// Finds works whose representative_attributes exceed the given size, largest first.
+// attrs_bytes is the JSON text size, stored_bytes the (possibly compressed) on-disk size.
+func (q *Queries) ListLargeWorkAttrs(ctx context.Context, arg ListLargeWorkAttrsParams) ([]ListLargeWorkAttrsRow, error) {
+	rows, err := q.db.Query(ctx, listLargeWorkAttrs, arg.MinBytes, arg.RowLimit, arg.RowOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLargeWorkAttrsRow
+	for rows.Next() {
+		var i ListLargeWorkAttrsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.AttrsBytes,
+			&i.StoredBytes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listManifestations = `-- name: ListManifestations :many
 SELECT r.id, r.entity_type, r.note, r.created_at, m.carrier_category, m.extent, m.intended_audience, m.manifestation_statement, m.access_conditions, m.use_rights
 FROM mp_res r
 JOIN mp_manifestation m ON r.id = m.id
 ORDER BY r.created_at DESC
+LIMIT $1
 `
 
 type ListManifestationsRow struct {
@@ -534,8 +893,8 @@ type ListManifestationsRow struct {
 	UseRights              []string           `json:"use_rights"`
 }
 
-func (q *Queries) ListManifestations(ctx context.Context) ([]ListManifestationsRow, error) {
-	rows, err := q.db.Query(ctx, listManifestations)
+func (q *Queries) ListManifestations(ctx context.Context, limit int32) ([]ListManifestationsRow, error) {
+	rows, err := q.db.Query(ctx, listManifestations, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -574,6 +933,7 @@ FROM mp_res r
 JOIN mp_agent a ON r.id = a.id
 JOIN mp_person p ON a.id = p.id
 ORDER BY r.created_at DESC
+LIMIT $1
 `
 
 type ListPeopleRow struct {
@@ -588,8 +948,8 @@ type ListPeopleRow struct {
 	Profession      []string           `json:"profession"`
 }
 
-func (q *Queries) ListPeople(ctx context.Context) ([]ListPeopleRow, error) {
-	rows, err := q.db.Query(ctx, listPeople)
+func (q *Queries) ListPeople(ctx context.Context, limit int32) ([]ListPeopleRow, error) {
+	rows, err := q.db.Query(ctx, listPeople, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -618,27 +978,56 @@ func (q *Queries) ListPeople(ctx context.Context) ([]ListPeopleRow, error) {
 	return items, nil
 }
 
-const listRes = `-- name: ListRes :many
-SELECT id, entity_type, note, created_at, updated_at
-FROM mp_res
-ORDER BY created_at DESC
+const listPeopleByFieldOfActivity = `-- name: ListPeopleByFieldOfActivity :many
+SELECT 
+    r.id, r.entity_type, r.note, r.created_at, r.updated_at,
+    a.contact_info, a.field_of_activity, a.language,
+    p.profession
+FROM mp_res r
+JOIN mp_agent a ON r.id = a.id
+JOIN mp_person p ON a.id = p.id
+WHERE mp_lower_array(a.field_of_activity) @> ARRAY[lower($1::text)]
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT $2 OFFSET $3
 `
 
-func (q *Queries) ListRes(ctx context.Context) ([]MpRe, error) {
-	rows, err := q.db.Query(ctx, listRes)
+type ListPeopleByFieldOfActivityParams struct {
+	FieldOfActivity string `json:"field_of_activity"`
+	RowLimit        int32  `json:"row_limit"`
+	RowOffset       int32  `json:"row_offset"`
+}
+
+type ListPeopleByFieldOfActivityRow struct {
+	ID              pgtype.UUID        `json:"id"`
+	EntityType      MpEntityType       `json:"entity_type"`
+	Note            []string           `json:"note"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	ContactInfo     []string           `json:"contact_info"`
+	FieldOfActivity []string           `json:"field_of_activity"`
+	Language        []string           `json:"language"`
+	Profession      []string           `json:"profession"`
+}
+
+func (q *Queries) ListPeopleByFieldOfActivity(ctx context.Context, arg ListPeopleByFieldOfActivityParams) ([]ListPeopleByFieldOfActivityRow, error) {
+	rows, err := q.db.Query(ctx, listPeopleByFieldOfActivity, arg.FieldOfActivity, arg.RowLimit, arg.RowOffset)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []MpRe
+	var items []ListPeopleByFieldOfActivityRow
 	for rows.Next() {
-		var i MpRe
+		var i ListPeopleByFieldOfActivityRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.EntityType,
 			&i.Note,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ContactInfo,
+			&i.FieldOfActivity,
+			&i.Language,
+			&i.Profession,
 		); err != nil {
 			return nil, err
 		}
@@ -650,38 +1039,64 @@ func (q *Queries) ListRes(ctx context.Context) ([]MpRe, error) {
 	return items, nil
 }
 
-const listWorks = `-- name: ListWorks :many
-SELECT r.id, r.entity_type, r.note, r.created_at, w.category, w.representative_attributes
+const listPeopleByLanguage = `-- name: ListPeopleByLanguage :many
+SELECT 
+    r.id, r.entity_type, r.note, r.created_at, r.updated_at,
+    a.contact_info, a.field_of_activity, a.language,
+    p.profession
 FROM mp_res r
-JOIN mp_work w ON r.id = w.id
-ORDER BY r.created_at DESC
+JOIN mp_agent a ON r.id = a.id
+JOIN mp_person p ON a.id = p.id
+WHERE mp_lower_array(a.language) @> ARRAY[lower($1::text)]
+AND ($2::text IS NULL OR mp_lower_array(a.field_of_activity) @> ARRAY[lower($2::text)])
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT $3 OFFSET $4
 `
 
-type ListWorksRow struct {
-	ID                       pgtype.UUID        `json:"id"`
-	EntityType               MpEntityType       `json:"entity_type"`
-	Note                     []string           `json:"note"`
-	CreatedAt                pgtype.Timestamptz `json:"created_at"`
-	Category                 []string           `json:"category"`
-	RepresentativeAttributes []byte             `json:"representative_attributes"`
+type ListPeopleByLanguageParams struct {
+	Language        string      `json:"language"`
+	FieldOfActivity pgtype.Text `json:"field_of_activity"`
+	RowLimit        int32       `json:"row_limit"`
+	RowOffset       int32       `json:"row_offset"`
+}
+
+type ListPeopleByLanguageRow struct {
+	ID              pgtype.UUID        `json:"id"`
+	EntityType      MpEntityType       `json:"entity_type"`
+	Note            []string           `json:"note"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	ContactInfo     []string           `json:"contact_info"`
+	FieldOfActivity []string           `json:"field_of_activity"`
+	Language        []string           `json:"language"`
+	Profession      []string           `json:"profession"`
 }
 
-func (q *Queries) ListWorks(ctx context.Context) ([]ListWorksRow, error) {
-	rows, err := q.db.Query(ctx, listWorks)
+// Optional extra filter: field_of_activity.
+func (q *Queries) ListPeopleByLanguage(ctx context.Context, arg ListPeopleByLanguageParams) ([]ListPeopleByLanguageRow, error) {
+	rows, err := q.db.Query(ctx, listPeopleByLanguage,
+		arg.Language,
+		arg.FieldOfActivity,
+		arg.RowLimit,
+		arg.RowOffset,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListWorksRow
+	var items []ListPeopleByLanguageRow
 	for rows.Next() {
-		var i ListWorksRow
+		var i ListPeopleByLanguageRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.EntityType,
 			&i.Note,
 			&i.CreatedAt,
-			&i.Category,
-			&i.RepresentativeAttributes,
+			&i.UpdatedAt,
+			&i.ContactInfo,
+			&i.FieldOfActivity,
+			&i.Language,
+			&i.Profession,
 		); err != nil {
 			return nil, err
 		}
@@ -692,3 +1107,727 @@ func (q *Queries) ListWorks(ctx context.Context) ([]ListWorksRow, error) {
 	}
 	return items, nil
 }
+
+const listPeopleByProfession = `-- name: ListPeopleByProfession :many
+SELECT 
+    r.id, r.entity_type, r.note, r.created_at, r.updated_at,
+    a.contact_info, a.field_of_activity, a.language,
+    p.profession
+FROM mp_res r
+JOIN mp_agent a ON r.id = a.id
+JOIN mp_person p ON a.id = p.id
+WHERE mp_lower_array(p.profession) @> ARRAY[lower($1::text)]
+AND ($2::text IS NULL OR mp_lower_array(a.language) @> ARRAY[lower($2::text)])
+AND ($3::text IS NULL OR mp_lower_array(a.field_of_activity) @> ARRAY[lower($3::text)])
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT $4 OFFSET $5
+`
+
+type ListPeopleByProfessionParams struct {
+	Profession      string      `json:"profession"`
+	Language        pgtype.Text `json:"language"`
+	FieldOfActivity pgtype.Text `json:"field_of_activity"`
+	RowLimit        int32       `json:"row_limit"`
+	RowOffset       int32       `json:"row_offset"`
+}
+
+type ListPeopleByProfessionRow struct {
+	ID              pgtype.UUID        `json:"id"`
+	EntityType      MpEntityType       `json:"entity_type"`
+	Note            []string           `json:"note"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	ContactInfo     []string           `json:"contact_info"`
+	FieldOfActivity []string           `json:"field_of_activity"`
+	Language        []string           `json:"language"`
+	Profession      []string           `json:"profession"`
+}
+
+// Optional extra filters: language, field_of_activity.
+func (q *Queries) ListPeopleByProfession(ctx context.Context, arg ListPeopleByProfessionParams) ([]ListPeopleByProfessionRow, error) {
+	rows, err := q.db.Query(ctx, listPeopleByProfession,
+		arg.Profession,
+		arg.Language,
+		arg.FieldOfActivity,
+		arg.RowLimit,
+		arg.RowOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPeopleByProfessionRow
+	for rows.Next() {
+		var i ListPeopleByProfessionRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EntityType,
+			&i.Note,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ContactInfo,
+			&i.FieldOfActivity,
+			&i.Language,
+			&i.Profession,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPeoplePage = `-- name: ListPeoplePage :many
+SELECT 
+    r.id, r.entity_type, r.note, r.created_at, r.updated_at,
+    a.contact_info, a.field_of_activity, a.language,
+    p.profession
+FROM mp_res r
+JOIN mp_agent a ON r.id = a.id
+JOIN mp_person p ON a.id = p.id
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT $1 OFFSET $2
+`
+
+type ListPeoplePageParams struct {
+	RowLimit  int32 `json:"row_limit"`
+	RowOffset int32 `json:"row_offset"`
+}
+
+type ListPeoplePageRow struct {
+	ID              pgtype.UUID        `json:"id"`
+	EntityType      MpEntityType       `json:"entity_type"`
+	Note            []string           `json:"note"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	ContactInfo     []string           `json:"contact_info"`
+	FieldOfActivity []string           `json:"field_of_activity"`
+	Language        []string           `json:"language"`
+	Profession      []string           `json:"profession"`
+}
+
+// Unfiltered. Filters go through the ListPeopleBy* variants, each led by one required
+// filter so that its mp_lower_array index stays usable under a generic plan.
+func (q *Queries) ListPeoplePage(ctx context.Context, arg ListPeoplePageParams) ([]ListPeoplePageRow, error) {
+	rows, err := q.db.Query(ctx, listPeoplePage, arg.RowLimit, arg.RowOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPeoplePageRow
+	for rows.Next() {
+		var i ListPeoplePageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EntityType,
+			&i.Note,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ContactInfo,
+			&i.FieldOfActivity,
+			&i.Language,
+			&i.Profession,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRes = `-- name: ListRes :many
+SELECT id, entity_type, note, created_at, updated_at
+FROM mp_res
+ORDER BY created_at DESC
+LIMIT $1
+`
+
+func (q *Queries) ListRes(ctx context.Context, limit int32) ([]MpRe, error) {
+	rows, err := q.db.Query(ctx, listRes, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MpRe
+	for rows.Next() {
+		var i MpRe
+		if err := rows.Scan(
+			&i.ID,
+			&i.EntityType,
+			&i.Note,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWorkAttrsBatch = `-- name: ListWorkAttrsBatch :many
+SELECT w.id, w.representative_attributes
+FROM mp_work w
+WHERE w.id > $1::uuid
+ORDER BY w.id
+LIMIT $2
+`
+
+type ListWorkAttrsBatchParams struct {
+	AfterID  pgtype.UUID `json:"after_id"`
+	RowLimit int32       `json:"row_limit"`
+}
+
+type ListWorkAttrsBatchRow struct {
+	ID                       pgtype.UUID     `json:"id"`
+	RepresentativeAttributes json.RawMessage `json:"representative_attributes"`
+}
+
+// Walks mp_work in id order for batch processing; pass the last id of the previous batch.
+func (q *Queries) ListWorkAttrsBatch(ctx context.Context, arg ListWorkAttrsBatchParams) ([]ListWorkAttrsBatchRow, error) {
+	rows, err := q.db.Query(ctx, listWorkAttrsBatch, arg.AfterID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListWorkAttrsBatchRow
+	for rows.Next() {
+		var i ListWorkAttrsBatchRow
+		if err := rows.Scan(&i.ID, &i.RepresentativeAttributes); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWorkContributors = `-- name: ListWorkContributors :many
+SELECT
+    c.id, c.agent_id, c.role, r.entity_type,
+    COALESCE(cr.label, c.role)::text AS role_label,
+    cr.priority AS role_priority
+FROM mp_contribution c
+JOIN mp_res r ON c.agent_id = r.id
+LEFT JOIN mp_contributor_role cr ON lower(c.role) = cr.role
+WHERE c.work_id = $1
+ORDER BY cr.priority ASC NULLS LAST, lower(c.role), c.created_at, c.id
+LIMIT $2
+`
+
+type ListWorkContributorsParams struct {
+	WorkID pgtype.UUID `json:"work_id"`
+	Limit  int32       `json:"limit"`
+}
+
+type ListWorkContributorsRow struct {
+	ID           pgtype.UUID  `json:"id"`
+	AgentID      pgtype.UUID  `json:"agent_id"`
+	Role         string       `json:"role"`
+	EntityType   MpEntityType `json:"entity_type"`
+	RoleLabel    string       `json:"role_label"`
+	RolePriority pgtype.Int4  `json:"role_priority"`
+}
+
+// Credits for a work in display order: by role priority from mp_contributor_role,
+// then alphabetically by role for roles without metadata.
+func (q *Queries) ListWorkContributors(ctx context.Context, arg ListWorkContributorsParams) ([]ListWorkContributorsRow, error) {
+	rows, err := q.db.Query(ctx, listWorkContributors, arg.WorkID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListWorkContributorsRow
+	for rows.Next() {
+		var i ListWorkContributorsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AgentID,
+			&i.Role,
+			&i.EntityType,
+			&i.RoleLabel,
+			&i.RolePriority,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWorks = `-- name: ListWorks :many
+SELECT r.id, r.entity_type, r.note, r.created_at, w.category, w.representative_attributes
+FROM mp_res r
+JOIN mp_work w ON r.id = w.id
+ORDER BY r.created_at DESC
+LIMIT $1
+`
+
+type ListWorksRow struct {
+	ID                       pgtype.UUID        `json:"id"`
+	EntityType               MpEntityType       `json:"entity_type"`
+	Note                     []string           `json:"note"`
+	CreatedAt                pgtype.Timestamptz `json:"created_at"`
+	Category                 []string           `json:"category"`
+	RepresentativeAttributes json.RawMessage    `json:"representative_attributes"`
+}
+
+func (q *Queries) ListWorks(ctx context.Context, limit int32) ([]ListWorksRow, error) {
+	rows, err := q.db.Query(ctx, listWorks, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListWorksRow
+	for rows.Next() {
+		var i ListWorksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EntityType,
+			&i.Note,
+			&i.CreatedAt,
+			&i.Category,
+			&i.RepresentativeAttributes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWorksAfter = `-- name: ListWorksAfter :many
+SELECT r.id, r.entity_type, r.note, r.created_at, w.category, w.representative_attributes
+FROM mp_res r
+JOIN mp_work w ON r.id = w.id
+WHERE (r.created_at, r.id) < ($1::timestamptz, $2::uuid)
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT $3
+`
+
+type ListWorksAfterParams struct {
+	AfterCreatedAt pgtype.Timestamptz `json:"after_created_at"`
+	AfterID        pgtype.UUID        `json:"after_id"`
+	RowLimit       int32              `json:"row_limit"`
+}
+
+type ListWorksAfterRow struct {
+	ID                       pgtype.UUID        `json:"id"`
+	EntityType               MpEntityType       `json:"entity_type"`
+	Note                     []string           `json:"note"`
+	CreatedAt                pgtype.Timestamptz `json:"created_at"`
+	Category                 []string           `json:"category"`
+	RepresentativeAttributes json.RawMessage    `json:"representative_attributes"`
+}
+
+// Keyset pagination: the page of works strictly after the given (created_at, id) position.
+func (q *Queries) ListWorksAfter(ctx context.Context, arg ListWorksAfterParams) ([]ListWorksAfterRow, error) {
+	rows, err := q.db.Query(ctx, listWorksAfter, arg.AfterCreatedAt, arg.AfterID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListWorksAfterRow
+	for rows.Next() {
+		var i ListWorksAfterRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EntityType,
+			&i.Note,
+			&i.CreatedAt,
+			&i.Category,
+			&i.RepresentativeAttributes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWorksAfterByCategory = `-- name: ListWorksAfterByCategory :many
+SELECT r.id, r.entity_type, r.note, r.created_at, w.category, w.representative_attributes
+FROM mp_res r
+JOIN mp_work w ON r.id = w.id
+WHERE (r.created_at, r.id) < ($1::timestamptz, $2::uuid)
+AND mp_lower_array(w.category) @> ARRAY[lower($3::text)]
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT $4
+`
+
+type ListWorksAfterByCategoryParams struct {
+	AfterCreatedAt pgtype.Timestamptz `json:"after_created_at"`
+	AfterID        pgtype.UUID        `json:"after_id"`
+	Category       string             `json:"category"`
+	RowLimit       int32              `json:"row_limit"`
+}
+
+type ListWorksAfterByCategoryRow struct {
+	ID                       pgtype.UUID        `json:"id"`
+	EntityType               MpEntityType       `json:"entity_type"`
+	Note                     []string           `json:"note"`
+	CreatedAt                pgtype.Timestamptz `json:"created_at"`
+	Category                 []string           `json:"category"`
+	RepresentativeAttributes json.RawMessage    `json:"representative_attributes"`
+}
+
+func (q *Queries) ListWorksAfterByCategory(ctx context.Context, arg ListWorksAfterByCategoryParams) ([]ListWorksAfterByCategoryRow, error) {
+	rows, err := q.db.Query(ctx, listWorksAfterByCategory,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Category,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListWorksAfterByCategoryRow
+	for rows.Next() {
+		var i ListWorksAfterByCategoryRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EntityType,
+			&i.Note,
+			&i.CreatedAt,
+			&i.Category,
+			&i.RepresentativeAttributes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWorksByContributorCount = `-- name: ListWorksByContributorCount :many
+SELECT r.id, r.entity_type, r.note, r.created_at, w.category, w.representative_attributes,
+       count(DISTINCT c.agent_id) AS contributor_count
+FROM mp_res r
+JOIN mp_work w ON r.id = w.id
+LEFT JOIN mp_contribution c ON c.work_id = w.id
+WHERE ($1::text IS NULL OR mp_lower_array(w.category) @> ARRAY[lower($1::text)])
+GROUP BY r.id, w.id
+HAVING ($2::int IS NULL OR count(DISTINCT c.agent_id) >= $2::int)
+AND ($3::int IS NULL OR count(DISTINCT c.agent_id) <= $3::int)
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT $4 OFFSET $5
+`
+
+type ListWorksByContributorCountParams struct {
+	Category        pgtype.Text `json:"category"`
+	MinContributors pgtype.Int4 `json:"min_contributors"`
+	MaxContributors pgtype.Int4 `json:"max_contributors"`
+	RowLimit        int32       `json:"row_limit"`
+	RowOffset       int32       `json:"row_offset"`
+}
+
+type ListWorksByContributorCountRow struct {
+	ID                       pgtype.UUID        `json:"id"`
+	EntityType               MpEntityType       `json:"entity_type"`
+	Note                     []string           `json:"note"`
+	CreatedAt                pgtype.Timestamptz `json:"created_at"`
+	Category                 []string           `json:"category"`
+	RepresentativeAttributes json.RawMessage    `json:"representative_attributes"`
+	ContributorCount         int64              `json:"contributor_count"`
+}
+
+// Works credited to between min_contributors and max_contributors distinct agents
+// (either bound optional). Works without contributions count as zero.
+func (q *Queries) ListWorksByContributorCount(ctx context.Context, arg ListWorksByContributorCountParams) ([]ListWorksByContributorCountRow, error) {
+	rows, err := q.db.Query(ctx, listWorksByContributorCount,
+		arg.Category,
+		arg.MinContributors,
+		arg.MaxContributors,
+		arg.RowLimit,
+		arg.RowOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListWorksByContributorCountRow
+	for rows.Next() {
+		var i ListWorksByContributorCountRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EntityType,
+			&i.Note,
+			&i.CreatedAt,
+			&i.Category,
+			&i.RepresentativeAttributes,
+			&i.ContributorCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWorksPage = `-- name: ListWorksPage :many
+SELECT r.id, r.entity_type, r.note, r.created_at, w.category, w.representative_attributes
+FROM mp_res r
+JOIN mp_work w ON r.id = w.id
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT $1 OFFSET $2
+`
+
+type ListWorksPageParams struct {
+	RowLimit  int32 `json:"row_limit"`
+	RowOffset int32 `json:"row_offset"`
+}
+
+type ListWorksPageRow struct {
+	ID                       pgtype.UUID        `json:"id"`
+	EntityType               MpEntityType       `json:"entity_type"`
+	Note                     []string           `json:"note"`
+	CreatedAt                pgtype.Timestamptz `json:"created_at"`
+	Category                 []string           `json:"category"`
+	RepresentativeAttributes json.RawMessage    `json:"representative_attributes"`
+}
+
+// Unfiltered; see ListWorksPageByCategory.
+func (q *Queries) ListWorksPage(ctx context.Context, arg ListWorksPageParams) ([]ListWorksPageRow, error) {
+	rows, err := q.db.Query(ctx, listWorksPage, arg.RowLimit, arg.RowOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListWorksPageRow
+	for rows.Next() {
+		var i ListWorksPageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EntityType,
+			&i.Note,
+			&i.CreatedAt,
+			&i.Category,
+			&i.RepresentativeAttributes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWorksPageByCategory = `-- name: ListWorksPageByCategory :many
+SELECT r.id, r.entity_type, r.note, r.created_at, w.category, w.representative_attributes
+FROM mp_res r
+JOIN mp_work w ON r.id = w.id
+WHERE mp_lower_array(w.category) @> ARRAY[lower($1::text)]
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListWorksPageByCategoryParams struct {
+	Category  string `json:"category"`
+	RowLimit  int32  `json:"row_limit"`
+	RowOffset int32  `json:"row_offset"`
+}
+
+type ListWorksPageByCategoryRow struct {
+	ID                       pgtype.UUID        `json:"id"`
+	EntityType               MpEntityType       `json:"entity_type"`
+	Note                     []string           `json:"note"`
+	CreatedAt                pgtype.Timestamptz `json:"created_at"`
+	Category                 []string           `json:"category"`
+	RepresentativeAttributes json.RawMessage    `json:"representative_attributes"`
+}
+
+// A separate query rather than an optional filter so that the category index stays
+// usable under a generic plan.
+func (q *Queries) ListWorksPageByCategory(ctx context.Context, arg ListWorksPageByCategoryParams) ([]ListWorksPageByCategoryRow, error) {
+	rows, err := q.db.Query(ctx, listWorksPageByCategory, arg.Category, arg.RowLimit, arg.RowOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListWorksPageByCategoryRow
+	for rows.Next() {
+		var i ListWorksPageByCategoryRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EntityType,
+			&i.Note,
+			&i.CreatedAt,
+			&i.Category,
+			&i.RepresentativeAttributes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const resolveResources = `-- name: ResolveResources :many
+SELECT
+    r.id, r.entity_type, r.note, r.created_at, r.updated_at,
+    a.contact_info, a.field_of_activity, a.language,
+    p.profession,
+    w.category, w.representative_attributes
+FROM mp_res r
+LEFT JOIN mp_agent a ON r.id = a.id
+LEFT JOIN mp_person p ON r.id = p.id
+LEFT JOIN mp_work w ON r.id = w.id
+WHERE r.id = ANY($1::uuid[])
+`
+
+type ResolveResourcesRow struct {
+	ID                       pgtype.UUID        `json:"id"`
+	EntityType               MpEntityType       `json:"entity_type"`
+	Note                     []string           `json:"note"`
+	CreatedAt                pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt                pgtype.Timestamptz `json:"updated_at"`
+	ContactInfo              []string           `json:"contact_info"`
+	FieldOfActivity          []string           `json:"field_of_activity"`
+	Language                 []string           `json:"language"`
+	Profession               []string           `json:"profession"`
+	Category                 []string           `json:"category"`
+	RepresentativeAttributes json.RawMessage    `json:"representative_attributes"`
+}
+
+// Fetches any mix of resources in one round trip. Subtype columns are NULL unless the
+// resource is of that subtype.
+func (q *Queries) ResolveResources(ctx context.Context, ids []pgtype.UUID) ([]ResolveResourcesRow, error) {
+	rows, err := q.db.Query(ctx, resolveResources, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ResolveResourcesRow
+	for rows.Next() {
+		var i ResolveResourcesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EntityType,
+			&i.Note,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ContactInfo,
+			&i.FieldOfActivity,
+			&i.Language,
+			&i.Profession,
+			&i.Category,
+			&i.RepresentativeAttributes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateAgent = `-- name: UpdateAgent :exec
+UPDATE mp_agent SET contact_info = $2, field_of_activity = $3, language = $4
+WHERE id = $1
+`
+
+type UpdateAgentParams struct {
+	ID              pgtype.UUID `json:"id"`
+	ContactInfo     []string    `json:"contact_info"`
+	FieldOfActivity []string    `json:"field_of_activity"`
+	Language        []string    `json:"language"`
+}
+
+func (q *Queries) UpdateAgent(ctx context.Context, arg UpdateAgentParams) error {
+	_, err := q.db.Exec(ctx, updateAgent,
+		arg.ID,
+		arg.ContactInfo,
+		arg.FieldOfActivity,
+		arg.Language,
+	)
+	return err
+}
+
+const updatePerson = `-- name: UpdatePerson :exec
+UPDATE mp_person SET profession = $2
+WHERE id = $1
+`
+
+type UpdatePersonParams struct {
+	ID         pgtype.UUID `json:"id"`
+	Profession []string    `json:"profession"`
+}
+
+func (q *Queries) UpdatePerson(ctx context.Context, arg UpdatePersonParams) error {
+	_, err := q.db.Exec(ctx, updatePerson, arg.ID, arg.Profession)
+	return err
+}
+
+const updateResNote = `-- name: UpdateResNote :exec
+UPDATE mp_res SET note = $2
+WHERE id = $1
+`
+
+type UpdateResNoteParams struct {
+	ID   pgtype.UUID `json:"id"`
+	Note []string    `json:"note"`
+}
+
+// Also bumps updated_at through the update_mp_res_modtime trigger.
+func (q *Queries) UpdateResNote(ctx context.Context, arg UpdateResNoteParams) error {
+	_, err := q.db.Exec(ctx, updateResNote, arg.ID, arg.Note)
+	return err
+}
+
+const upsertDraft = `-- name: UpsertDraft :exec
+INSERT INTO mp_draft (session_id, kind, data, expires_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (session_id, kind)
+DO UPDATE SET data = EXCLUDED.data, updated_at = now(), expires_at = EXCLUDED.expires_at
+`
+
+type UpsertDraftParams struct {
+	SessionID string             `json:"session_id"`
+	Kind      string             `json:"kind"`
+	Data      []byte             `json:"data"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) UpsertDraft(ctx context.Context, arg UpsertDraftParams) error {
+	_, err := q.db.Exec(ctx, upsertDraft,
+		arg.SessionID,
+		arg.Kind,
+		arg.Data,
+		arg.ExpiresAt,
+	)
+	return err
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"mangaparty/db"
+)
+
+const (
+	// sessionCookie identifies a browser for draft storage. It carries no authority.
+	sessionCookie = "mp_session"
+	// maxDraftBytes bounds a single draft; drafts hold form state, not documents.
+	maxDraftBytes = 64 << 10
+)
+
+// draftKinds are the create forms that support auto-save.
+var draftKinds = map[string]bool{
+	"person": true,
+	"work":   true,
+}
+
+// DraftResponse is returned when restoring a draft.
+type DraftResponse struct {
+	Kind      string             `json:"kind"`
+	Data      json.RawMessage    `json:"data"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+// draftKind validates the {kind} path segment.
+func draftKind(w http.ResponseWriter, r *http.Request) (string, bool) {
+	kind := r.PathValue("kind")
+	if !draftKinds[kind] {
+		http.Error(w, "Unknown draft kind", http.StatusNotFound)
+		return "", false
+	}
+	return kind, true
+}
+
+// sessionID returns the caller's session id from the session cookie, if any.
+func sessionID(r *http.Request) (string, bool) {
+	if c, err := r.Cookie(sessionCookie); err == nil {
+		if _, err := uuid.Parse(c.Value); err == nil {
+			return c.Value, true
+		}
+	}
+	return "", false
+}
+
+// setSessionCookie (re)issues the session cookie so that it outlives the browser
+// session and expires together with the caller's newest draft.
+func setSessionCookie(w http.ResponseWriter, id string, ttl time.Duration) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    id,
+		Path:     "/",
+		MaxAge:   int(ttl.Seconds()),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// handleSaveDraft stores the in-progress state of a create form. Each save replaces
+// the previous draft and pushes its expiry, and the session cookie's, DRAFT_TTL into
+// the future.
+func (s *Server) handleSaveDraft(w http.ResponseWriter, r *http.Request) {
+	kind, ok := draftKind(w, r)
+	if !ok {
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxDraftBytes))
+	if err != nil {
+		http.Error(w, "Draft too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(body, &obj); err != nil || obj == nil {
+		http.Error(w, "Draft must be a JSON object", http.StatusBadRequest)
+		return
+	}
+
+	session, ok := sessionID(r)
+	if !ok {
+		session = uuid.NewString()
+	}
+	setSessionCookie(w, session, s.cfg.DraftTTL)
+	expiresAt := time.Now().Add(s.cfg.DraftTTL)
+	err = s.queries.UpsertDraft(r.Context(), db.UpsertDraftParams{
+		SessionID: session,
+		Kind:      kind,
+		Data:      body,
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
+	if err != nil {
+		http.Error(w, "Failed to save draft: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleGetDraft restores the caller's draft for a form, if one has not expired.
+func (s *Server) handleGetDraft(w http.ResponseWriter, r *http.Request) {
+	kind, ok := draftKind(w, r)
+	if !ok {
+		return
+	}
+	session, ok := sessionID(r)
+	if !ok {
+		http.Error(w, "Draft not found", http.StatusNotFound)
+		return
+	}
+
+	draft, err := s.queries.GetDraft(r.Context(), db.GetDraftParams{SessionID: session, Kind: kind})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "Draft not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Database error: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(DraftResponse{
+		Kind:      kind,
+		Data:      draft.Data,
+		UpdatedAt: draft.UpdatedAt,
+		ExpiresAt: draft.ExpiresAt,
+	})
+}
+
+// handleDeleteDraft discards a draft, typically after the form was submitted.
+func (s *Server) handleDeleteDraft(w http.ResponseWriter, r *http.Request) {
+	kind, ok := draftKind(w, r)
+	if !ok {
+		return
+	}
+	if session, ok := sessionID(r); ok {
+		err := s.queries.DeleteDraft(r.Context(), db.DeleteDraftParams{SessionID: session, Kind: kind})
+		if err != nil {
+			http.Error(w, "Failed to delete draft: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
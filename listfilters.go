@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"mangaparty/db"
+)
+
+// The filtered list queries are separate queries rather than optional arguments of the
+// unfiltered ones. pgx caches prepared statements, and once Postgres switches such a
+// statement to a generic plan a "$1 IS NULL OR ..." condition can no longer use the
+// mp_lower_array GIN indexes. Each filtered query instead leads with one required
+// filter, and the helpers below pick the query from the filters that are present.
+
+// peopleFilter holds the optional array filters of GET /api/people.
+type peopleFilter struct {
+	profession      pgtype.Text
+	language        pgtype.Text
+	fieldOfActivity pgtype.Text
+}
+
+// peoplePage returns one page of people matching f, and how many match in total.
+func (s *Server) peoplePage(ctx context.Context, f peopleFilter, p page) ([]db.ListPeoplePageRow, int64, error) {
+	var people []db.ListPeoplePageRow
+	var total int64
+	var err error
+	switch {
+	case f.profession.Valid:
+		var rows []db.ListPeopleByProfessionRow
+		rows, err = s.queries.ListPeopleByProfession(ctx, db.ListPeopleByProfessionParams{
+			Profession:      f.profession.String,
+			Language:        f.language,
+			FieldOfActivity: f.fieldOfActivity,
+			RowLimit:        p.Limit,
+			RowOffset:       p.Offset,
+		})
+		for _, row := range rows {
+			people = append(people, db.ListPeoplePageRow(row))
+		}
+		if err == nil {
+			total, err = s.queries.CountPeopleByProfession(ctx, db.CountPeopleByProfessionParams{
+				Profession:      f.profession.String,
+				Language:        f.language,
+				FieldOfActivity: f.fieldOfActivity,
+			})
+		}
+	case f.language.Valid:
+		var rows []db.ListPeopleByLanguageRow
+		rows, err = s.queries.ListPeopleByLanguage(ctx, db.ListPeopleByLanguageParams{
+			Language:        f.language.String,
+			FieldOfActivity: f.fieldOfActivity,
+			RowLimit:        p.Limit,
+			RowOffset:       p.Offset,
+		})
+		for _, row := range rows {
+			people = append(people, db.ListPeoplePageRow(row))
+		}
+		if err == nil {
+			total, err = s.queries.CountPeopleByLanguage(ctx, db.CountPeopleByLanguageParams{
+				Language:        f.language.String,
+				FieldOfActivity: f.fieldOfActivity,
+			})
+		}
+	case f.fieldOfActivity.Valid:
+		var rows []db.ListPeopleByFieldOfActivityRow
+		rows, err = s.queries.ListPeopleByFieldOfActivity(ctx, db.ListPeopleByFieldOfActivityParams{
+			FieldOfActivity: f.fieldOfActivity.String,
+			RowLimit:        p.Limit,
+			RowOffset:       p.Offset,
+		})
+		for _, row := range rows {
+			people = append(people, db.ListPeoplePageRow(row))
+		}
+		if err == nil {
+			total, err = s.queries.CountPeopleByFieldOfActivity(ctx, f.fieldOfActivity.String)
+		}
+	default:
+		people, err = s.queries.ListPeoplePage(ctx, db.ListPeoplePageParams{RowLimit: p.Limit, RowOffset: p.Offset})
+		if err == nil {
+			total, err = s.queries.CountPeople(ctx)
+		}
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("fetch people: %w", err)
+	}
+	return people, total, nil
+}
+
+// worksPage returns the works at offset p.Offset, optionally filtered by category.
+func (s *Server) worksPage(ctx context.Context, category pgtype.Text, p page) ([]db.ListWorksPageRow, error) {
+	if !category.Valid {
+		return s.queries.ListWorksPage(ctx, db.ListWorksPageParams{RowLimit: p.Limit, RowOffset: p.Offset})
+	}
+	rows, err := s.queries.ListWorksPageByCategory(ctx, db.ListWorksPageByCategoryParams{
+		Category:  category.String,
+		RowLimit:  p.Limit,
+		RowOffset: p.Offset,
+	})
+	works := make([]db.ListWorksPageRow, 0, len(rows))
+	for _, row := range rows {
+		works = append(works, db.ListWorksPageRow(row))
+	}
+	return works, err
+}
+
+// worksAfter returns the works strictly after the keyset position (createdAt, id),
+// optionally filtered by category.
+func (s *Server) worksAfter(ctx context.Context, category pgtype.Text, createdAt pgtype.Timestamptz, id pgtype.UUID, limit int32) ([]db.ListWorksPageRow, error) {
+	var works []db.ListWorksPageRow
+	if !category.Valid {
+		rows, err := s.queries.ListWorksAfter(ctx, db.ListWorksAfterParams{
+			AfterCreatedAt: createdAt,
+			AfterID:        id,
+			RowLimit:       limit,
+		})
+		for _, row := range rows {
+			works = append(works, db.ListWorksPageRow(row))
+		}
+		return works, err
+	}
+	rows, err := s.queries.ListWorksAfterByCategory(ctx, db.ListWorksAfterByCategoryParams{
+		AfterCreatedAt: createdAt,
+		AfterID:        id,
+		Category:       category.String,
+		RowLimit:       limit,
+	})
+	for _, row := range rows {
+		works = append(works, db.ListWorksPageRow(row))
+	}
+	return works, err
+}
+
+// countWorks returns how many works match the optional category filter.
+func (s *Server) countWorks(ctx context.Context, category pgtype.Text) (int64, error) {
+	if !category.Valid {
+		return s.queries.CountWorks(ctx)
+	}
+	return s.queries.CountWorksByCategory(ctx, category.String)
+}
@@ -3,10 +3,14 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"expvar"
+	"flag"
+	"fmt"
 	"html/template"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -21,9 +25,24 @@ type Server struct {
 	queries *db.Queries
 	pool    *pgxpool.Pool
 	tmpl    *template.Template
+	cfg     Config
+	norm    normalizer
+
+	languageStats cachedJSON
 }
 
 func main() {
+	checkTemplatesOnly := flag.Bool("check-templates", false, "execute every page template against sample data and exit (non-zero on failure)")
+	flag.Parse()
+
+	if *checkTemplatesOnly {
+		if err := checkTemplates(); err != nil {
+			log.Fatalf("Template check failed:\n%v", err)
+		}
+		log.Println("All templates OK.")
+		return
+	}
+
 	// Determine environment
 	env := os.Getenv("APP_ENV")
 	if env == "" {
@@ -61,12 +80,18 @@ func main() {
 		log.Fatalf("Failed to parse templates: %v", err)
 	}
 
+	cfg := loadConfig()
 	srv := &Server{
 		queries: db.New(pool),
 		pool:    pool,
 		tmpl:    tmpl,
+		cfg:     cfg,
+		norm:    newNormalizer(cfg),
 	}
 
+	// Background cleanup of expired rows (e.g. form drafts)
+	go srv.runReaper(context.Background(), srv.cfg.ReaperInterval)
+
 	// 2. Setup API routes
 	mux := http.NewServeMux()
 
@@ -83,8 +108,23 @@ func main() {
 
 	// API Routes
 	mux.HandleFunc("POST /api/person", srv.handleCreatePerson)
+	mux.HandleFunc("GET /api/people", srv.handleAPIListPeople)
 	mux.HandleFunc("GET /api/person/{id}", srv.handleGetPerson)
+	mux.HandleFunc("PATCH /api/person/{id}", srv.handlePatchPerson)
+	mux.HandleFunc("GET /api/works", srv.handleAPIListWorks)
 	mux.HandleFunc("POST /api/work", srv.handleCreateWork)
+	mux.HandleFunc("GET /api/work/{id}/contributors", srv.handleListContributors)
+	mux.HandleFunc("POST /api/work/{id}/contributors", srv.handleCreateContribution)
+	mux.HandleFunc("GET /api/stats/languages", srv.handleLanguageStats)
+	mux.HandleFunc("POST /api/resources/resolve", srv.handleResolveResources)
+	mux.HandleFunc("POST /api/drafts/{kind}", srv.handleSaveDraft)
+	mux.HandleFunc("GET /api/drafts/{kind}", srv.handleGetDraft)
+	mux.HandleFunc("DELETE /api/drafts/{kind}", srv.handleDeleteDraft)
+
+	// Admin Routes
+	mux.HandleFunc("GET /api/admin/large-attrs", srv.requireAdmin(srv.handleLargeAttrs))
+	mux.HandleFunc("GET /api/admin/validate-attrs", srv.requireAdmin(srv.handleValidateAttrs))
+	mux.Handle("GET /debug/vars", srv.requireAdmin(expvar.Handler().ServeHTTP))
 	// Add more handlers here as you build out the API...
 
 	// 3. Start the web server
@@ -107,11 +147,12 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleListPeople(w http.ResponseWriter, r *http.Request) {
-	people, err := s.queries.ListPeople(r.Context())
+	people, err := s.queries.ListPeople(r.Context(), s.rowLimit())
 	if err != nil {
 		http.Error(w, "Failed to fetch people: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	people, _ = capRows(w, people, s.cfg.MaxResultRows)
 	s.render(w, "person_list.html", people)
 }
 
@@ -120,11 +161,12 @@ func (s *Server) handleNewPerson(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleListWorks(w http.ResponseWriter, r *http.Request) {
-	works, err := s.queries.ListWorks(r.Context())
+	works, err := s.queries.ListWorks(r.Context(), s.rowLimit())
 	if err != nil {
 		http.Error(w, "Failed to fetch works: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	works, _ = capRows(w, works, s.cfg.MaxResultRows)
 	s.render(w, "work_list.html", works)
 }
 
@@ -147,7 +189,7 @@ func (s *Server) render(w http.ResponseWriter, name string, data interface{}) {
 	// Let's refactor the ParseGlob approach to a per-request parse for simplicity and correctness with "content" blocks.
 
 	// Re-parsing for simplicity in this demo. In prod, use a map of pre-parsed templates.
-	t, err := template.ParseFiles("templates/base.html", "templates/"+name)
+	t, err := parsePageTemplate(name)
 	if err != nil {
 		http.Error(w, "Template error: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -170,6 +212,24 @@ type CreatePersonRequest struct {
 	Profession []string `json:"profession"`
 }
 
+// createPersonParams builds the rows of a new person, applying the array write
+// policies to every field. The IDs are filled in once the root row exists.
+func (s *Server) createPersonParams(req CreatePersonRequest) (db.CreateResParams, db.CreateAgentParams, db.CreatePersonParams) {
+	res := db.CreateResParams{
+		EntityType: db.MpEntityTypePerson, // This is the enum sqlc generated for you
+		Note:       s.norm.array(req.Note),
+	}
+	agent := db.CreateAgentParams{
+		ContactInfo:     s.norm.array(req.Contact),
+		FieldOfActivity: s.norm.array(req.Activity),
+		Language:        s.norm.values(req.Language),
+	}
+	person := db.CreatePersonParams{
+		Profession: s.norm.values(req.Profession),
+	}
+	return res, agent, person
+}
+
 // handleCreatePerson demonstrates a transaction for the Class Table Inheritance model.
 func (s *Server) handleCreatePerson(w http.ResponseWriter, r *http.Request) {
 	var req CreatePersonRequest
@@ -178,55 +238,32 @@ func (s *Server) handleCreatePerson(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx := r.Context()
-
-	// START TRANSACTION: Creating a person requires 3 inserts, which must all succeed or fail together.
-	tx, err := s.pool.Begin(ctx)
-	if err != nil {
-		http.Error(w, "Failed to begin transaction", http.StatusInternalServerError)
-		return
-	}
-	// Defer a rollback. If the transaction is committed, this is a no-op.
-	defer tx.Rollback(ctx)
-
-	// Use the transaction-aware querier
-	qtx := s.queries.WithTx(tx)
-
-	// Step 1: Insert into the root table `mp_res`
-	res, err := qtx.CreateRes(ctx, db.CreateResParams{
-		EntityType: db.MpEntityTypePerson, // This is the enum sqlc generated for you
-		Note:       req.Note,
-	})
-	if err != nil {
-		http.Error(w, "Failed to create base resource: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
+	// Creating a person requires 3 inserts, which must all succeed or fail together.
+	resParams, agentParams, personParams := s.createPersonParams(req)
+	var res db.CreateResRow
+	err := s.withTx(r.Context(), "create person", func(ctx context.Context, qtx *db.Queries) error {
+		// Step 1: Insert into the root table `mp_res`
+		var err error
+		res, err = qtx.CreateRes(ctx, resParams)
+		if err != nil {
+			return fmt.Errorf("create base resource: %w", err)
+		}
 
-	// Step 2: Insert into the `mp_agent` table using the ID from the root table
-	err = qtx.CreateAgent(ctx, db.CreateAgentParams{
-		ID:              res.ID,
-		ContactInfo:     req.Contact,
-		FieldOfActivity: req.Activity,
-		Language:        req.Language,
-	})
-	if err != nil {
-		http.Error(w, "Failed to create agent: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
+		// Step 2: Insert into the `mp_agent` table using the ID from the root table
+		agentParams.ID = res.ID
+		if err := qtx.CreateAgent(ctx, agentParams); err != nil {
+			return fmt.Errorf("create agent: %w", err)
+		}
 
-	// Step 3: Insert into the `mp_person` table
-	err = qtx.CreatePerson(ctx, db.CreatePersonParams{
-		ID:         res.ID,
-		Profession: req.Profession,
+		// Step 3: Insert into the `mp_person` table
+		personParams.ID = res.ID
+		if err := qtx.CreatePerson(ctx, personParams); err != nil {
+			return fmt.Errorf("create person: %w", err)
+		}
+		return nil
 	})
 	if err != nil {
-		http.Error(w, "Failed to create person: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	// COMMIT TRANSACTION: If all steps were successful, commit the changes.
-	if err := tx.Commit(ctx); err != nil {
-		http.Error(w, "Failed to commit transaction", http.StatusInternalServerError)
+		writeTxError(w, err)
 		return
 	}
 
@@ -255,56 +292,214 @@ func (s *Server) handleGetPerson(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", personETag(person))
+	w.Header().Set("Accept-Patch", mergePatchType)
 	json.NewEncoder(w).Encode(person)
 }
 
-// CreateWorkRequest defines the JSON payload for creating a new work.
-type CreateWorkRequest struct {
-	Note                     []string        `json:"note"`
-	Category                 []string        `json:"category"`
-	RepresentativeAttributes json.RawMessage `json:"representative_attributes"` // JSONB
+// handleAPIListPeople returns a page of people, newest first, using ?limit= and ?offset=.
+// ?profession=, ?language= and ?field_of_activity= keep only people whose array
+// contains the value, ignoring case.
+func (s *Server) handleAPIListPeople(w http.ResponseWriter, r *http.Request) {
+	p, err := s.parsePage(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	q := r.URL.Query()
+	filter := peopleFilter{
+		profession:      textParam(q, "profession"),
+		language:        textParam(q, "language"),
+		fieldOfActivity: textParam(q, "field_of_activity"),
+	}
+
+	people, total, err := s.peoplePage(r.Context(), filter, p)
+	if err != nil {
+		http.Error(w, "Failed to "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	setOffsetLinks(w, r, p, total)
+	writeList(w, nonNil(people), pageMeta{Limit: p.Limit, Offset: p.Offset, Total: &total, Truncated: p.truncated(total)})
 }
 
-func (s *Server) handleCreateWork(w http.ResponseWriter, r *http.Request) {
-	var req CreateWorkRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+// handleAPIListWorks returns a page of works, newest first. It uses offset paging by
+// default; passing ?cursor= (empty for the first page) switches to keyset paging,
+// which stays stable while works are being added. ?category= filters works whose
+// categories contain the value, ignoring case. ?min_contributors= and
+// ?max_contributors= restrict the list by number of credited agents; they use offset
+// paging only and each row then also carries its contributor_count.
+func (s *Server) handleAPIListWorks(w http.ResponseWriter, r *http.Request) {
+	p, err := s.parsePage(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	q := r.URL.Query()
+	category := textParam(q, "category")
+	if q.Has("min_contributors") || q.Has("max_contributors") {
+		if q.Has("cursor") {
+			http.Error(w, "Contributor count filters cannot be combined with cursor", http.StatusBadRequest)
+			return
+		}
+		s.listWorksByContributorCount(w, r, p, category)
+		return
+	}
+	if q.Has("cursor") {
+		s.listWorksByCursor(w, r, p, category)
 		return
 	}
 
 	ctx := r.Context()
-	tx, err := s.pool.Begin(ctx)
+	works, err := s.worksPage(ctx, category, p)
+	if err != nil {
+		http.Error(w, "Failed to fetch works: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	total, err := s.countWorks(ctx, category)
 	if err != nil {
-		http.Error(w, "Failed to begin transaction", http.StatusInternalServerError)
+		http.Error(w, "Failed to count works: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-	defer tx.Rollback(ctx)
 
-	qtx := s.queries.WithTx(tx)
+	setOffsetLinks(w, r, p, total)
+	writeList(w, nonNil(works), pageMeta{Limit: p.Limit, Offset: p.Offset, Total: &total, Truncated: p.truncated(total)})
+}
 
-	// Step 1: Insert into mp_res
-	res, err := qtx.CreateRes(ctx, db.CreateResParams{
-		EntityType: db.MpEntityTypeWork,
-		Note:       req.Note,
+func (s *Server) listWorksByContributorCount(w http.ResponseWriter, r *http.Request, p page, category pgtype.Text) {
+	q := r.URL.Query()
+	var bounds [2]pgtype.Int4
+	for i, key := range []string{"min_contributors", "max_contributors"} {
+		if v := q.Get(key); v != "" {
+			n, err := strconv.ParseInt(v, 10, 32)
+			if err != nil || n < 0 {
+				http.Error(w, "Invalid "+key, http.StatusBadRequest)
+				return
+			}
+			bounds[i] = pgtype.Int4{Int32: int32(n), Valid: true}
+		}
+	}
+	minCount, maxCount := bounds[0], bounds[1]
+	if minCount.Valid && maxCount.Valid && minCount.Int32 > maxCount.Int32 {
+		http.Error(w, "min_contributors must not exceed max_contributors", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	works, err := s.queries.ListWorksByContributorCount(ctx, db.ListWorksByContributorCountParams{
+		Category:        category,
+		MinContributors: minCount,
+		MaxContributors: maxCount,
+		RowLimit:        p.Limit,
+		RowOffset:       p.Offset,
 	})
 	if err != nil {
-		http.Error(w, "Failed to create base resource: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Failed to fetch works: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-
-	// Step 2: Insert into mp_work
-	err = qtx.CreateWork(ctx, db.CreateWorkParams{
-		ID:                       res.ID,
-		Category:                 req.Category,
-		RepresentativeAttributes: req.RepresentativeAttributes,
+	total, err := s.queries.CountWorksByContributorCount(ctx, db.CountWorksByContributorCountParams{
+		Category:        category,
+		MinContributors: minCount,
+		MaxContributors: maxCount,
 	})
 	if err != nil {
-		http.Error(w, "Failed to create work: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Failed to count works: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	if err := tx.Commit(ctx); err != nil {
-		http.Error(w, "Failed to commit transaction", http.StatusInternalServerError)
+	setOffsetLinks(w, r, p, total)
+	writeList(w, nonNil(works), pageMeta{Limit: p.Limit, Offset: p.Offset, Total: &total, Truncated: p.truncated(total)})
+}
+
+func (s *Server) listWorksByCursor(w http.ResponseWriter, r *http.Request, p page, category pgtype.Text) {
+	ctx := r.Context()
+	var works []db.ListWorksPageRow
+	if cursor := r.URL.Query().Get("cursor"); cursor == "" {
+		rows, err := s.worksPage(ctx, category, page{Limit: p.Limit})
+		if err != nil {
+			http.Error(w, "Failed to fetch works: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		works = rows
+	} else {
+		createdAt, id, err := decodeCursor(cursor)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rows, err := s.worksAfter(ctx, category, createdAt, id, p.Limit)
+		if err != nil {
+			http.Error(w, "Failed to fetch works: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		works = rows
+	}
+
+	var next string
+	if len(works) == int(p.Limit) {
+		last := works[len(works)-1]
+		next = encodeCursor(last.CreatedAt, last.ID)
+	}
+
+	setCursorLinks(w, r, p, next)
+	writeList(w, nonNil(works), pageMeta{Limit: p.Limit, NextCursor: next, Truncated: p.Clamped && next != ""})
+}
+
+// CreateWorkRequest defines the JSON payload for creating a new work.
+type CreateWorkRequest struct {
+	Note                     []string        `json:"note"`
+	Category                 []string        `json:"category"`
+	RepresentativeAttributes json.RawMessage `json:"representative_attributes"` // JSONB
+}
+
+// createWorkParams builds the rows of a new work, applying the array write policies
+// to every array field. The ID is filled in once the root row exists.
+func (s *Server) createWorkParams(req CreateWorkRequest) (db.CreateResParams, db.CreateWorkParams) {
+	res := db.CreateResParams{
+		EntityType: db.MpEntityTypeWork,
+		Note:       s.norm.array(req.Note),
+	}
+	work := db.CreateWorkParams{
+		Category:                 s.norm.values(req.Category),
+		RepresentativeAttributes: req.RepresentativeAttributes,
+	}
+	return res, work
+}
+
+func (s *Server) handleCreateWork(w http.ResponseWriter, r *http.Request) {
+	var req CreateWorkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	resParams, workParams := s.createWorkParams(req)
+	var res db.CreateResRow
+	err := s.withTx(r.Context(), "create work", func(ctx context.Context, qtx *db.Queries) error {
+		// Step 1: Size the attributes as Postgres stores them
+		size, err := qtx.AttrsTextSize(ctx, req.RepresentativeAttributes)
+		if err != nil {
+			return fmt.Errorf("measure representative_attributes: %w", err)
+		}
+		if err := s.checkAttrsSize(int(size)); err != nil {
+			return &clientError{http.StatusRequestEntityTooLarge, err.Error()}
+		}
+
+		// Step 2: Insert into mp_res
+		res, err = qtx.CreateRes(ctx, resParams)
+		if err != nil {
+			return fmt.Errorf("create base resource: %w", err)
+		}
+
+		// Step 3: Insert into mp_work
+		workParams.ID = res.ID
+		if err := qtx.CreateWork(ctx, workParams); err != nil {
+			return fmt.Errorf("create work: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		writeTxError(w, err)
 		return
 	}
 
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// arrayFields returns every []string field of the structs in params, by name.
+func arrayFields(params ...interface{}) map[string][]string {
+	fields := make(map[string][]string)
+	for _, p := range params {
+		v := reflect.ValueOf(p)
+		for i := 0; i < v.NumField(); i++ {
+			if f, ok := v.Field(i).Interface().([]string); ok {
+				fields[v.Type().Name()+"."+v.Type().Field(i).Name] = f
+			}
+		}
+	}
+	return fields
+}
+
+// TestCreateParamsEmptyArrays checks that the create handlers send every array column
+// through the EMPTY_ARRAYS policy, for omitted fields as well as explicit [].
+func TestCreateParamsEmptyArrays(t *testing.T) {
+	empty := []string{}
+	for _, policy := range []struct {
+		emptyArrays string
+		want        string
+	}{
+		{emptyArraysEmpty, "{}"},
+		{emptyArraysNull, "NULL"},
+	} {
+		for _, normalize := range []bool{false, true} {
+			s := &Server{norm: testNormalizer(policy.emptyArrays, normalize)}
+			for name, req := range map[string]struct {
+				person CreatePersonRequest
+				work   CreateWorkRequest
+			}{
+				"omitted": {},
+				"empty": {
+					CreatePersonRequest{Note: empty, Contact: empty, Activity: empty, Language: empty, Profession: empty},
+					CreateWorkRequest{Note: empty, Category: empty},
+				},
+			} {
+				res, agent, person := s.createPersonParams(req.person)
+				workRes, work := s.createWorkParams(req.work)
+				fields := arrayFields(res, agent, person)
+				for k, v := range arrayFields(workRes, work) {
+					fields["work "+k] = v
+				}
+				// mp_res.note and four person columns, mp_res.note and mp_work.category.
+				if len(fields) != 7 {
+					t.Fatalf("found %d array fields, want 7: %v", len(fields), fields)
+				}
+				for field, v := range fields {
+					if got := storedArray(t, v); got != policy.want {
+						t.Errorf("%s/%s/normalize=%v: %s stored %s, want %s",
+							policy.emptyArrays, name, normalize, field, got, policy.want)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,11 @@
+package main
+
+import "expvar"
+
+// Counters exposed through expvar at /debug/vars (admin only).
+var (
+	// attrsOversizeTotal counts writes whose representative_attributes exceeded Config.AttrsWarnBytes.
+	attrsOversizeTotal = expvar.NewInt("attrs_oversize_total")
+	// txRollbacks counts rolled-back transactions keyed by "<stage>.<cause>", e.g. "step.client".
+	txRollbacks = expvar.NewMap("tx_rollbacks")
+)
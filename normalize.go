@@ -0,0 +1,63 @@
+package main
+
+import "strings"
+
+// normalizer implements the write-time policies for array columns: how empty arrays
+// are stored (all arrays) and value normalization (category, language, profession).
+type normalizer struct {
+	enabled bool
+	// emptyAsNull stores empty arrays as NULL rather than '{}'.
+	emptyAsNull bool
+	// exceptions maps the lowercased form of an allowlisted value to its canonical spelling.
+	exceptions map[string]string
+}
+
+func newNormalizer(cfg Config) normalizer {
+	n := normalizer{
+		enabled:     cfg.NormalizeValues,
+		emptyAsNull: cfg.EmptyArrays == emptyArraysNull,
+		exceptions:  make(map[string]string),
+	}
+	for _, e := range cfg.NormalizeExceptions {
+		n.exceptions[strings.ToLower(e)] = e
+	}
+	return n
+}
+
+// values trims and lowercases each value, keeps allowlisted values in their canonical
+// spelling, and drops blanks and duplicates that only differed by case or whitespace.
+// Order is preserved. When normalization is disabled the values are left as they are.
+// Either way the result goes through array, so it is safe to write directly.
+func (n normalizer) values(in []string) []string {
+	if !n.enabled || in == nil {
+		return n.array(in)
+	}
+	out := make([]string, 0, len(in))
+	seen := make(map[string]bool, len(in))
+	for _, v := range in {
+		key := strings.ToLower(strings.TrimSpace(v))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		if canonical, ok := n.exceptions[key]; ok {
+			out = append(out, canonical)
+		} else {
+			out = append(out, key)
+		}
+	}
+	return n.array(out)
+}
+
+// array applies the EMPTY_ARRAYS policy to a value about to be written to a TEXT[]
+// column. pgx sends a nil slice as NULL and a non-nil empty slice as '{}', so a
+// missing JSON field and [] would otherwise be stored differently.
+func (n normalizer) array(in []string) []string {
+	if len(in) > 0 {
+		return in
+	}
+	if n.emptyAsNull {
+		return nil
+	}
+	return []string{}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// storedArray returns how Postgres receives v for a TEXT[] column: "NULL" or the
+// array literal pgx encodes, e.g. "{}".
+func storedArray(t *testing.T, v []string) string {
+	t.Helper()
+	buf, err := pgtype.NewMap().Encode(pgtype.TextArrayOID, pgtype.TextFormatCode, v, nil)
+	if err != nil {
+		t.Fatalf("encode %#v: %v", v, err)
+	}
+	if buf == nil {
+		return "NULL"
+	}
+	return string(buf)
+}
+
+func testNormalizer(emptyArrays string, normalize bool) normalizer {
+	return newNormalizer(Config{EmptyArrays: emptyArrays, NormalizeValues: normalize})
+}
+
+func TestNormalizerArray(t *testing.T) {
+	tests := []struct {
+		name        string
+		emptyArrays string
+		in          []string
+		want        string
+	}{
+		{"nil as null", emptyArraysNull, nil, "NULL"},
+		{"empty as null", emptyArraysNull, []string{}, "NULL"},
+		{"nil as empty", emptyArraysEmpty, nil, "{}"},
+		{"empty as empty", emptyArraysEmpty, []string{}, "{}"},
+		{"values kept under null", emptyArraysNull, []string{"Manga"}, "{Manga}"},
+		{"values kept under empty", emptyArraysEmpty, []string{"Manga"}, "{Manga}"},
+		{"whitespace kept under null", emptyArraysNull, []string{"  "}, `{"  "}`},
+		{"whitespace kept under empty", emptyArraysEmpty, []string{"  "}, `{"  "}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := storedArray(t, testNormalizer(tt.emptyArrays, false).array(tt.in))
+			if got != tt.want {
+				t.Errorf("stored %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizerValues(t *testing.T) {
+	tests := []struct {
+		name        string
+		emptyArrays string
+		normalize   bool
+		in          []string
+		want        string
+	}{
+		{"off nil as null", emptyArraysNull, false, nil, "NULL"},
+		{"off empty as null", emptyArraysNull, false, []string{}, "NULL"},
+		{"off nil as empty", emptyArraysEmpty, false, nil, "{}"},
+		{"off empty as empty", emptyArraysEmpty, false, []string{}, "{}"},
+		{"off whitespace untouched", emptyArraysNull, false, []string{" Manga "}, `{" Manga "}`},
+		{"on nil as null", emptyArraysNull, true, nil, "NULL"},
+		{"on empty as null", emptyArraysNull, true, []string{}, "NULL"},
+		{"on nil as empty", emptyArraysEmpty, true, nil, "{}"},
+		{"on empty as empty", emptyArraysEmpty, true, []string{}, "{}"},
+		{"on whitespace-only as null", emptyArraysNull, true, []string{" ", "\t"}, "NULL"},
+		{"on whitespace-only as empty", emptyArraysEmpty, true, []string{" ", "\t"}, "{}"},
+		{"on values normalized", emptyArraysNull, true, []string{" Manga ", "manga", "Comics"}, "{manga,comics}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := storedArray(t, testNormalizer(tt.emptyArrays, tt.normalize).values(tt.in))
+			if got != tt.want {
+				t.Errorf("stored %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
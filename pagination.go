@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+// truncatedHeader tells clients that a response hit the server-side row cap and
+// that they should paginate to see the rest.
+const truncatedHeader = "X-Result-Truncated"
+
+// page is the parsed ?limit= / ?offset= of a list request.
+type page struct {
+	Limit  int32
+	Offset int32
+	// Clamped is set when the requested limit was above the page maximum or the row cap.
+	Clamped bool
+}
+
+// truncated reports whether clamping the limit held back rows of an offset page, given
+// how many rows match in total.
+func (p page) truncated(total int64) bool {
+	return p.Clamped && int64(p.Offset)+int64(p.Limit) < total
+}
+
+// pageMeta is the paging information returned alongside every list response.
+type pageMeta struct {
+	Limit      int32  `json:"limit"`
+	Offset     int32  `json:"offset"`
+	Total      *int64 `json:"total,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Truncated  bool   `json:"truncated"`
+}
+
+// listResponse is the envelope for all JSON list endpoints.
+type listResponse struct {
+	Data interface{} `json:"data"`
+	Meta pageMeta    `json:"meta"`
+}
+
+// parsePage reads ?limit= and ?offset=. Limits above the page maximum or the row cap
+// are clamped; the response is only flagged as truncated if that held rows back.
+func (s *Server) parsePage(r *http.Request) (page, error) {
+	maxLimit := min(maxPageLimit, s.cfg.MaxResultRows)
+	p := page{Limit: int32(min(defaultPageLimit, maxLimit))}
+	q := r.URL.Query()
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return p, errors.New("invalid limit")
+		}
+		p.Limit = int32(min(n, maxLimit))
+		p.Clamped = n > maxLimit
+	}
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || n < 0 {
+			return p, errors.New("invalid offset")
+		}
+		p.Offset = int32(n)
+	}
+	return p, nil
+}
+
+// pageURL returns the request URL with the given query parameters replaced.
+func pageURL(r *http.Request, set map[string]string) string {
+	q := r.URL.Query()
+	for k, v := range set {
+		q.Set(k, v)
+	}
+	u := url.URL{Path: r.URL.Path, RawQuery: q.Encode()}
+	return u.String()
+}
+
+// setOffsetLinks emits an RFC 5988 Link header with first/prev/next/last relations
+// for an offset-paginated list of total rows.
+func setOffsetLinks(w http.ResponseWriter, r *http.Request, p page, total int64) {
+	link := func(offset int64, rel string) string {
+		u := pageURL(r, map[string]string{
+			"limit":  strconv.Itoa(int(p.Limit)),
+			"offset": strconv.FormatInt(offset, 10),
+		})
+		return fmt.Sprintf("<%s>; rel=%q", u, rel)
+	}
+
+	limit, offset := int64(p.Limit), int64(p.Offset)
+	last := int64(0)
+	if total > 0 {
+		last = (total - 1) / limit * limit
+	}
+
+	links := []string{link(0, "first")}
+	if offset > 0 {
+		links = append(links, link(max(offset-limit, 0), "prev"))
+	}
+	if offset+limit < total {
+		links = append(links, link(offset+limit, "next"))
+	}
+	links = append(links, link(last, "last"))
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
+
+// setCursorLinks emits a Link header for a keyset-paginated list. next is omitted
+// on the last page (empty cursor).
+func setCursorLinks(w http.ResponseWriter, r *http.Request, p page, next string) {
+	link := func(cursor, rel string) string {
+		q := r.URL.Query()
+		q.Del("offset")
+		q.Set("limit", strconv.Itoa(int(p.Limit)))
+		q.Set("cursor", cursor)
+		u := url.URL{Path: r.URL.Path, RawQuery: q.Encode()}
+		return fmt.Sprintf("<%s>; rel=%q", u.String(), rel)
+	}
+
+	links := []string{link("", "first")}
+	if next != "" {
+		links = append(links, link(next, "next"))
+	}
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
+
+// encodeCursor builds an opaque keyset cursor from the last row of a page.
+func encodeCursor(createdAt pgtype.Timestamptz, id pgtype.UUID) string {
+	raw := createdAt.Time.UTC().Format(time.RFC3339Nano) + "|" + uuid.UUID(id.Bytes).String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (pgtype.Timestamptz, pgtype.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return pgtype.Timestamptz{}, pgtype.UUID{}, errors.New("invalid cursor")
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return pgtype.Timestamptz{}, pgtype.UUID{}, errors.New("invalid cursor")
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return pgtype.Timestamptz{}, pgtype.UUID{}, errors.New("invalid cursor")
+	}
+	u, err := uuid.Parse(id)
+	if err != nil {
+		return pgtype.Timestamptz{}, pgtype.UUID{}, errors.New("invalid cursor")
+	}
+	return pgtype.Timestamptz{Time: t, Valid: true}, pgtype.UUID{Bytes: u, Valid: true}, nil
+}
+
+// textParam turns an optional query parameter into a nullable query argument.
+func textParam(q url.Values, key string) pgtype.Text {
+	v := strings.TrimSpace(q.Get(key))
+	return pgtype.Text{String: v, Valid: v != ""}
+}
+
+// nonNil keeps empty result sets encoding as [] rather than null.
+func nonNil[T any](rows []T) []T {
+	if rows == nil {
+		return []T{}
+	}
+	return rows
+}
+
+// rowLimit is the LIMIT to pass to unpaged multi-row queries: one more than the cap,
+// so that capRows can tell a result that exactly fits from one that was cut off.
+func (s *Server) rowLimit() int32 {
+	return int32(s.cfg.MaxResultRows + 1)
+}
+
+// capRows trims rows fetched with rowLimit to the row cap and reports whether
+// anything was cut off, setting the truncation header if so.
+func capRows[T any](w http.ResponseWriter, rows []T, maxRows int) ([]T, bool) {
+	if len(rows) <= maxRows {
+		return rows, false
+	}
+	w.Header().Set(truncatedHeader, "true")
+	return rows[:maxRows], true
+}
+
+func writeList(w http.ResponseWriter, data interface{}, meta pageMeta) {
+	if meta.Truncated {
+		w.Header().Set(truncatedHeader, "true")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(listResponse{Data: data, Meta: meta})
+}
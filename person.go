@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"mangaparty/db"
+)
+
+// mergePatchType is the media type of RFC 7396 JSON Merge Patch documents.
+const mergePatchType = "application/merge-patch+json"
+
+// personETag is a strong validator for a person's current representation.
+func personETag(p db.GetPersonRow) string {
+	b, _ := json.Marshal(p)
+	sum := sha256.Sum256(b)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// ifMatch reports whether an If-Match header value admits the given ETag. An absent
+// header always matches; "*" matches any existing resource.
+func ifMatch(header, etag string) bool {
+	if header == "" {
+		return true
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// preferReturn returns the value of the return preference (RFC 7240) in the request's
+// Prefer headers, "minimal" or "representation", or "" when none was expressed.
+func preferReturn(r *http.Request) string {
+	for _, header := range r.Header.Values("Prefer") {
+		for _, pref := range strings.Split(header, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(pref), "=")
+			if !strings.EqualFold(strings.TrimSpace(name), "return") {
+				continue
+			}
+			value, _, _ = strings.Cut(value, ";")
+			switch value = strings.Trim(strings.TrimSpace(value), `"`); value {
+			case "minimal", "representation":
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// applyPersonPatch merges a JSON Merge Patch object into p. Only the person's array
+// fields may be patched; every other key is an error. Cleared and replaced fields are
+// stored according to the EMPTY_ARRAYS policy.
+func (s *Server) applyPersonPatch(p *db.GetPersonRow, patch map[string]json.RawMessage) error {
+	fields := map[string]*[]string{
+		"note":              &p.Note,
+		"contact_info":      &p.ContactInfo,
+		"field_of_activity": &p.FieldOfActivity,
+		"language":          &p.Language,
+		"profession":        &p.Profession,
+	}
+
+	keys := make([]string, 0, len(patch))
+	for k := range patch {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		dst, ok := fields[key]
+		if !ok {
+			return fmt.Errorf("%s: unknown or read-only field", key)
+		}
+		var values []string
+		if raw := patch[key]; string(raw) != "null" {
+			if err := json.Unmarshal(raw, &values); err != nil {
+				return fmt.Errorf("%s: must be an array of strings or null", key)
+			}
+		}
+		if key == "language" || key == "profession" {
+			*dst = s.norm.values(values)
+		} else {
+			*dst = s.norm.array(values)
+		}
+	}
+	return nil
+}
+
+// handlePatchPerson updates a person with a JSON Merge Patch (RFC 7396).
+//
+// The request must be sent as Content-Type: application/merge-patch+json with a JSON
+// object body. For each of note, contact_info, field_of_activity, language and
+// profession:
+//
+//   - an absent key leaves the field unchanged,
+//   - null clears the field,
+//   - an array of strings replaces the field (arrays are replaced, never merged).
+//
+// Any other key, including the read-only id, entity_type, created_at and updated_at,
+// is rejected with 400. When If-Match is sent and does not match the person's current
+// ETag the patch is refused with 412. The read, precondition check and writes happen
+// in one transaction with the person's row locked, so concurrent patches serialize.
+//
+// The response is 200 with the updated person, or 204 with only the new ETag when the
+// client sends Prefer: return=minimal.
+func (s *Server) handlePatchPerson(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != mergePatchType {
+		w.Header().Set("Accept-Patch", mergePatchType)
+		http.Error(w, "Content-Type must be "+mergePatchType, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	personID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+	id := pgtype.UUID{Bytes: personID, Valid: true}
+
+	var patch map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil || patch == nil {
+		http.Error(w, "Merge patch must be a JSON object", http.StatusBadRequest)
+		return
+	}
+
+	var updated db.GetPersonRow
+	err = s.withTx(r.Context(), "patch person", func(ctx context.Context, qtx *db.Queries) error {
+		// Step 1: Lock and load the current state
+		current, err := qtx.GetPersonForUpdate(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return &clientError{http.StatusNotFound, "Person not found"}
+			}
+			return fmt.Errorf("load person: %w", err)
+		}
+		person := db.GetPersonRow(current)
+		if !ifMatch(r.Header.Get("If-Match"), personETag(person)) {
+			return &clientError{http.StatusPreconditionFailed, "Person has been modified"}
+		}
+
+		// Step 2: Merge the patch in memory
+		if err := s.applyPersonPatch(&person, patch); err != nil {
+			return &clientError{http.StatusBadRequest, "Invalid merge patch: " + err.Error()}
+		}
+
+		// Step 3: Write every table of the inheritance chain back
+		if err := qtx.UpdateResNote(ctx, db.UpdateResNoteParams{ID: id, Note: person.Note}); err != nil {
+			return fmt.Errorf("update base resource: %w", err)
+		}
+		err = qtx.UpdateAgent(ctx, db.UpdateAgentParams{
+			ID:              id,
+			ContactInfo:     person.ContactInfo,
+			FieldOfActivity: person.FieldOfActivity,
+			Language:        person.Language,
+		})
+		if err != nil {
+			return fmt.Errorf("update agent: %w", err)
+		}
+		if err := qtx.UpdatePerson(ctx, db.UpdatePersonParams{ID: id, Profession: person.Profession}); err != nil {
+			return fmt.Errorf("update person: %w", err)
+		}
+
+		// Step 4: Re-read so the response carries the new updated_at
+		updated, err = qtx.GetPerson(ctx, id)
+		if err != nil {
+			return fmt.Errorf("reload person: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		writeTxError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", personETag(updated))
+	w.Header().Set("Vary", "Prefer")
+	pref := preferReturn(r)
+	if pref != "" {
+		w.Header().Set("Preference-Applied", "return="+pref)
+	}
+	if pref == "minimal" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
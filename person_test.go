@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"mangaparty/db"
+)
+
+func TestApplyPersonPatchEmptyArrays(t *testing.T) {
+	tests := []struct {
+		name        string
+		emptyArrays string
+		patch       string
+		want        string
+	}{
+		{"null as null", emptyArraysNull, `{"note": null, "profession": null}`, "NULL"},
+		{"empty as null", emptyArraysNull, `{"note": [], "profession": []}`, "NULL"},
+		{"null as empty", emptyArraysEmpty, `{"note": null, "profession": null}`, "{}"},
+		{"empty as empty", emptyArraysEmpty, `{"note": [], "profession": []}`, "{}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{norm: testNormalizer(tt.emptyArrays, false)}
+			var patch map[string]json.RawMessage
+			if err := json.Unmarshal([]byte(tt.patch), &patch); err != nil {
+				t.Fatal(err)
+			}
+			p := db.GetPersonRow{
+				Note:       []string{"Debuted in 1989"},
+				Profession: []string{"Mangaka"},
+				Language:   []string{"Japanese"},
+			}
+			if err := s.applyPersonPatch(&p, patch); err != nil {
+				t.Fatalf("applyPersonPatch: %v", err)
+			}
+			// note goes through array, profession through values.
+			if got := storedArray(t, p.Note); got != tt.want {
+				t.Errorf("note stored %s, want %s", got, tt.want)
+			}
+			if got := storedArray(t, p.Profession); got != tt.want {
+				t.Errorf("profession stored %s, want %s", got, tt.want)
+			}
+			if got := storedArray(t, p.Language); got != "{Japanese}" {
+				t.Errorf("unpatched language stored %s, want {Japanese}", got)
+			}
+		})
+	}
+}
+
+func TestApplyPersonPatchRejects(t *testing.T) {
+	s := &Server{norm: testNormalizer(emptyArraysNull, false)}
+	for _, patch := range []string{`{"id": null}`, `{"note": "text"}`, `{"note": [1]}`} {
+		var m map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(patch), &m); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.applyPersonPatch(&db.GetPersonRow{}, m); err == nil {
+			t.Errorf("%s: want error", patch)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// runReaper periodically deletes rows that have outlived their TTL. It returns when
+// ctx is cancelled.
+func (s *Server) runReaper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.reap(ctx)
+		}
+	}
+}
+
+func (s *Server) reap(ctx context.Context) {
+	n, err := s.queries.DeleteExpiredDrafts(ctx)
+	if err != nil {
+		log.Printf("Reaper: failed to delete expired drafts: %v", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("Reaper: deleted %d expired drafts", n)
+	}
+}
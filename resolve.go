@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"mangaparty/db"
+)
+
+// ResolveRequest defines the JSON payload for resolving a batch of resource ids.
+type ResolveRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// AgentFields are the attributes every agent subtype shares (mp_agent).
+type AgentFields struct {
+	ContactInfo     []string `json:"contact_info"`
+	FieldOfActivity []string `json:"field_of_activity"`
+	Language        []string `json:"language"`
+}
+
+// PersonFields are the attributes specific to a person (mp_person).
+type PersonFields struct {
+	Profession []string `json:"profession"`
+}
+
+// WorkFields are the subtype attributes of a work (mp_work).
+type WorkFields struct {
+	Category                 []string        `json:"category"`
+	RepresentativeAttributes json.RawMessage `json:"representative_attributes"`
+}
+
+// ResolvedResource is a root resource plus the fields of its own subtype: agents and
+// collective agents carry the agent fields, people the agent and person fields. Other
+// subtypes are returned with the root fields only.
+type ResolvedResource struct {
+	db.MpRe
+	*AgentFields
+	*PersonFields
+	*WorkFields
+}
+
+// ResolveResponse lists the resolved resources in request order, plus the
+// requested ids that do not exist.
+type ResolveResponse struct {
+	Data    []ResolvedResource `json:"data"`
+	Missing []string           `json:"missing"`
+}
+
+// handleResolveResources looks up a heterogeneous list of resource ids in a single
+// query, so clients can render mixed reference lists without a request per id.
+func (s *Server) handleResolveResources(w http.ResponseWriter, r *http.Request) {
+	var req ResolveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(req.IDs) > s.cfg.MaxResultRows {
+		http.Error(w, fmt.Sprintf("At most %d ids can be resolved per request", s.cfg.MaxResultRows), http.StatusBadRequest)
+		return
+	}
+
+	// Parse and de-duplicate, remembering the order the client asked in.
+	order := make([]uuid.UUID, 0, len(req.IDs))
+	ids := make([]pgtype.UUID, 0, len(req.IDs))
+	seen := make(map[uuid.UUID]bool, len(req.IDs))
+	for _, raw := range req.IDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			http.Error(w, "Invalid UUID format: "+raw, http.StatusBadRequest)
+			return
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		order = append(order, id)
+		ids = append(ids, pgtype.UUID{Bytes: id, Valid: true})
+	}
+
+	rows, err := s.queries.ResolveResources(r.Context(), ids)
+	if err != nil {
+		http.Error(w, "Database error: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	found := make(map[uuid.UUID]db.ResolveResourcesRow, len(rows))
+	for _, row := range rows {
+		found[uuid.UUID(row.ID.Bytes)] = row
+	}
+
+	resp := ResolveResponse{Data: []ResolvedResource{}, Missing: []string{}}
+	for _, id := range order {
+		row, ok := found[id]
+		if !ok {
+			resp.Missing = append(resp.Missing, id.String())
+			continue
+		}
+		resp.Data = append(resp.Data, resolvedResource(row))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func resolvedResource(row db.ResolveResourcesRow) ResolvedResource {
+	res := ResolvedResource{MpRe: db.MpRe{
+		ID:         row.ID,
+		EntityType: row.EntityType,
+		Note:       row.Note,
+		CreatedAt:  row.CreatedAt,
+		UpdatedAt:  row.UpdatedAt,
+	}}
+	switch row.EntityType {
+	case db.MpEntityTypeAgent, db.MpEntityTypeCollectiveAgent, db.MpEntityTypePerson:
+		res.AgentFields = &AgentFields{
+			ContactInfo:     row.ContactInfo,
+			FieldOfActivity: row.FieldOfActivity,
+			Language:        row.Language,
+		}
+		if row.EntityType == db.MpEntityTypePerson {
+			res.PersonFields = &PersonFields{Profession: row.Profession}
+		}
+	case db.MpEntityTypeWork:
+		res.WorkFields = &WorkFields{
+			Category:                 row.Category,
+			RepresentativeAttributes: row.RepresentativeAttributes,
+		}
+	}
+	return res
+}
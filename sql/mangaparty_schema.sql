@@ -14,6 +14,13 @@ BEGIN
 END;
 $$ language 'plpgsql';
 
+-- Lowercases every element of a text array. Declared IMMUTABLE so it can back the
+-- expression indexes used by case-insensitive array-contains filters.
+CREATE OR REPLACE FUNCTION mp_lower_array(arr TEXT[])
+RETURNS TEXT[] AS $$
+    SELECT array_agg(lower(x)) FROM unnest(arr) AS x;
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
 -- ==================================================================
 -- 1. ENUMS
 -- ==================================================================
@@ -345,4 +352,62 @@ CREATE INDEX idx_mp_nomen_string ON mp_nomen(nomen_string);
 -- CREATE INDEX idx_mp_nomen_string_trgm ON mp_nomen USING gin (nomen_string gin_trgm_ops);
 
 -- Indexes for Discriminators
-CREATE INDEX idx_mp_res_entity_type ON mp_res(entity_type);
\ No newline at end of file
+CREATE INDEX idx_mp_res_entity_type ON mp_res(entity_type);
+
+-- Indexes for case-insensitive array filters, e.g. mp_lower_array(profession) @> ARRAY['artist']
+CREATE INDEX idx_mp_person_profession_ci ON mp_person USING gin (mp_lower_array(profession));
+CREATE INDEX idx_mp_agent_language_ci ON mp_agent USING gin (mp_lower_array(language));
+CREATE INDEX idx_mp_agent_field_of_activity_ci ON mp_agent USING gin (mp_lower_array(field_of_activity));
+CREATE INDEX idx_mp_work_category_ci ON mp_work USING gin (mp_lower_array(category));
+
+-- ==================================================================
+-- 10. APPLICATION STATE (Not part of the LRM model)
+-- ==================================================================
+
+CREATE TABLE mp_draft (
+  session_id TEXT NOT NULL,
+  kind TEXT NOT NULL,
+  data JSONB NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  expires_at TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (session_id, kind)
+);
+
+COMMENT ON TABLE mp_draft IS 'Auto-saved state of unsubmitted create forms, keyed by browser session. Expired rows are deleted by the reaper.';
+COMMENT ON COLUMN mp_draft.kind IS 'Which form the draft belongs to, e.g. person, work';
+
+CREATE INDEX idx_mp_draft_expires ON mp_draft(expires_at);
+
+-- ==================================================================
+-- 11. CONTRIBUTIONS (Credits)
+-- ==================================================================
+
+CREATE TABLE mp_contributor_role (
+  role TEXT PRIMARY KEY,
+  label TEXT NOT NULL,
+  priority INT NOT NULL DEFAULT 100
+);
+
+COMMENT ON TABLE mp_contributor_role IS 'Role metadata for credit lists. Edit to change the display order of roles.';
+COMMENT ON COLUMN mp_contributor_role.role IS 'Lowercase role key, matched case-insensitively against mp_contribution.role';
+COMMENT ON COLUMN mp_contributor_role.priority IS 'Lower values are listed first; roles without metadata sort last, alphabetically';
+
+INSERT INTO mp_contributor_role (role, label, priority) VALUES
+  ('writer', 'Writer', 10),
+  ('artist', 'Artist', 20),
+  ('translator', 'Translator', 30),
+  ('letterer', 'Letterer', 40),
+  ('editor', 'Editor', 50);
+
+CREATE TABLE mp_contribution (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  work_id UUID NOT NULL REFERENCES mp_work(id) ON DELETE CASCADE,
+  agent_id UUID NOT NULL REFERENCES mp_agent(id) ON DELETE CASCADE,
+  role TEXT NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT now(),
+  UNIQUE (work_id, agent_id, role)
+);
+
+COMMENT ON TABLE mp_contribution IS 'Credits an Agent with a role on a Work (a typed refinement of MP_R5).';
+
+CREATE INDEX idx_mp_contribution_agent ON mp_contribution(agent_id);
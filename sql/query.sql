@@ -6,7 +6,8 @@ RETURNING id, created_at;
 -- name: ListRes :many
 SELECT id, entity_type, note, created_at, updated_at
 FROM mp_res
-ORDER BY created_at DESC;
+ORDER BY created_at DESC
+LIMIT $1;
 
 -- name: CreateAgent :exec
 INSERT INTO mp_agent (id, contact_info, field_of_activity, language)
@@ -35,7 +36,8 @@ SELECT
 FROM mp_res r
 JOIN mp_agent a ON r.id = a.id
 JOIN mp_person p ON a.id = p.id
-ORDER BY r.created_at DESC;
+ORDER BY r.created_at DESC
+LIMIT $1;
 
 -- name: CreateWork :exec
 INSERT INTO mp_work (id, category, representative_attributes)
@@ -51,7 +53,8 @@ WHERE r.id = $1;
 SELECT r.id, r.entity_type, r.note, r.created_at, w.category, w.representative_attributes
 FROM mp_res r
 JOIN mp_work w ON r.id = w.id
-ORDER BY r.created_at DESC;
+ORDER BY r.created_at DESC
+LIMIT $1;
 
 -- name: CreateExpression :exec
 INSERT INTO mp_expression (id, category, extent, intended_audience, use_rights, cartographic_scale, language, musical_key, medium_of_performance)
@@ -67,7 +70,8 @@ WHERE r.id = $1;
 SELECT r.id, r.entity_type, r.note, r.created_at, e.category, e.extent, e.intended_audience, e.use_rights, e.cartographic_scale, e.language, e.musical_key, e.medium_of_performance
 FROM mp_res r
 JOIN mp_expression e ON r.id = e.id
-ORDER BY r.created_at DESC;
+ORDER BY r.created_at DESC
+LIMIT $1;
 
 -- name: CreateManifestation :exec
 INSERT INTO mp_manifestation (id, carrier_category, extent, intended_audience, manifestation_statement, access_conditions, use_rights)
@@ -83,7 +87,8 @@ WHERE r.id = $1;
 SELECT r.id, r.entity_type, r.note, r.created_at, m.carrier_category, m.extent, m.intended_audience, m.manifestation_statement, m.access_conditions, m.use_rights
 FROM mp_res r
 JOIN mp_manifestation m ON r.id = m.id
-ORDER BY r.created_at DESC;
+ORDER BY r.created_at DESC
+LIMIT $1;
 
 -- name: CreateItem :exec
 INSERT INTO mp_item (id, location, use_rights)
@@ -99,7 +104,8 @@ WHERE r.id = $1;
 SELECT r.id, r.entity_type, r.note, r.created_at, i.location, i.use_rights
 FROM mp_res r
 JOIN mp_item i ON r.id = i.id
-ORDER BY r.created_at DESC;
+ORDER BY r.created_at DESC
+LIMIT $1;
 
 -- name: CreateRelationship :one
 INSERT INTO mp_relationship (source_id, target_id, rel_type, note)
@@ -114,4 +120,272 @@ FROM mp_relationship rel
 JOIN mp_res r ON rel.source_id = r.id
 JOIN mp_work w ON r.id = w.id
 WHERE rel.target_id = $1 -- The agent's ID
-AND rel.rel_type = 'MP_R5'; -- 'Work was created by Agent'
\ No newline at end of file
+AND rel.rel_type = 'MP_R5' -- 'Work was created by Agent'
+LIMIT $2;
+
+-- name: ListLargeWorkAttrs :many
+-- Finds works whose representative_attributes exceed the given size, largest first.
+-- attrs_bytes is the JSON text size, stored_bytes the (possibly compressed) on-disk size.
+SELECT
+    r.id, r.created_at,
+    octet_length(w.representative_attributes::text)::int AS attrs_bytes,
+    pg_column_size(w.representative_attributes) AS stored_bytes
+FROM mp_res r
+JOIN mp_work w ON r.id = w.id
+WHERE octet_length(w.representative_attributes::text) > sqlc.arg(min_bytes)::int
+ORDER BY attrs_bytes DESC, r.id
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: CountLargeWorkAttrs :one
+SELECT count(*)
+FROM mp_work w
+WHERE octet_length(w.representative_attributes::text) > sqlc.arg(min_bytes)::int;
+
+-- name: AttrsTextSize :one
+-- Size of a representative_attributes value measured exactly as ListLargeWorkAttrs does;
+-- 0 for NULL.
+SELECT coalesce(octet_length(sqlc.narg(attrs)::jsonb::text), 0)::int AS size;
+
+-- name: ListPeoplePage :many
+-- Unfiltered. Filters go through the ListPeopleBy* variants, each led by one required
+-- filter so that its mp_lower_array index stays usable under a generic plan.
+SELECT 
+    r.id, r.entity_type, r.note, r.created_at, r.updated_at,
+    a.contact_info, a.field_of_activity, a.language,
+    p.profession
+FROM mp_res r
+JOIN mp_agent a ON r.id = a.id
+JOIN mp_person p ON a.id = p.id
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: ListPeopleByProfession :many
+-- Optional extra filters: language, field_of_activity.
+SELECT 
+    r.id, r.entity_type, r.note, r.created_at, r.updated_at,
+    a.contact_info, a.field_of_activity, a.language,
+    p.profession
+FROM mp_res r
+JOIN mp_agent a ON r.id = a.id
+JOIN mp_person p ON a.id = p.id
+WHERE mp_lower_array(p.profession) @> ARRAY[lower(sqlc.arg(profession)::text)]
+AND (sqlc.narg(language)::text IS NULL OR mp_lower_array(a.language) @> ARRAY[lower(sqlc.narg(language)::text)])
+AND (sqlc.narg(field_of_activity)::text IS NULL OR mp_lower_array(a.field_of_activity) @> ARRAY[lower(sqlc.narg(field_of_activity)::text)])
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: ListPeopleByLanguage :many
+-- Optional extra filter: field_of_activity.
+SELECT 
+    r.id, r.entity_type, r.note, r.created_at, r.updated_at,
+    a.contact_info, a.field_of_activity, a.language,
+    p.profession
+FROM mp_res r
+JOIN mp_agent a ON r.id = a.id
+JOIN mp_person p ON a.id = p.id
+WHERE mp_lower_array(a.language) @> ARRAY[lower(sqlc.arg(language)::text)]
+AND (sqlc.narg(field_of_activity)::text IS NULL OR mp_lower_array(a.field_of_activity) @> ARRAY[lower(sqlc.narg(field_of_activity)::text)])
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: ListPeopleByFieldOfActivity :many
+SELECT 
+    r.id, r.entity_type, r.note, r.created_at, r.updated_at,
+    a.contact_info, a.field_of_activity, a.language,
+    p.profession
+FROM mp_res r
+JOIN mp_agent a ON r.id = a.id
+JOIN mp_person p ON a.id = p.id
+WHERE mp_lower_array(a.field_of_activity) @> ARRAY[lower(sqlc.arg(field_of_activity)::text)]
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: CountPeople :one
+SELECT count(*)
+FROM mp_person;
+
+-- name: CountPeopleByProfession :one
+SELECT count(*)
+FROM mp_agent a
+JOIN mp_person p ON a.id = p.id
+WHERE mp_lower_array(p.profession) @> ARRAY[lower(sqlc.arg(profession)::text)]
+AND (sqlc.narg(language)::text IS NULL OR mp_lower_array(a.language) @> ARRAY[lower(sqlc.narg(language)::text)])
+AND (sqlc.narg(field_of_activity)::text IS NULL OR mp_lower_array(a.field_of_activity) @> ARRAY[lower(sqlc.narg(field_of_activity)::text)]);
+
+-- name: CountPeopleByLanguage :one
+SELECT count(*)
+FROM mp_agent a
+JOIN mp_person p ON a.id = p.id
+WHERE mp_lower_array(a.language) @> ARRAY[lower(sqlc.arg(language)::text)]
+AND (sqlc.narg(field_of_activity)::text IS NULL OR mp_lower_array(a.field_of_activity) @> ARRAY[lower(sqlc.narg(field_of_activity)::text)]);
+
+-- name: CountPeopleByFieldOfActivity :one
+SELECT count(*)
+FROM mp_agent a
+JOIN mp_person p ON a.id = p.id
+WHERE mp_lower_array(a.field_of_activity) @> ARRAY[lower(sqlc.arg(field_of_activity)::text)];
+
+-- name: ListWorksPage :many
+-- Unfiltered; see ListWorksPageByCategory.
+SELECT r.id, r.entity_type, r.note, r.created_at, w.category, w.representative_attributes
+FROM mp_res r
+JOIN mp_work w ON r.id = w.id
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: ListWorksPageByCategory :many
+-- A separate query rather than an optional filter so that the category index stays
+-- usable under a generic plan.
+SELECT r.id, r.entity_type, r.note, r.created_at, w.category, w.representative_attributes
+FROM mp_res r
+JOIN mp_work w ON r.id = w.id
+WHERE mp_lower_array(w.category) @> ARRAY[lower(sqlc.arg(category)::text)]
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: ListWorksAfter :many
+-- Keyset pagination: the page of works strictly after the given (created_at, id) position.
+SELECT r.id, r.entity_type, r.note, r.created_at, w.category, w.representative_attributes
+FROM mp_res r
+JOIN mp_work w ON r.id = w.id
+WHERE (r.created_at, r.id) < (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::uuid)
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListWorksAfterByCategory :many
+SELECT r.id, r.entity_type, r.note, r.created_at, w.category, w.representative_attributes
+FROM mp_res r
+JOIN mp_work w ON r.id = w.id
+WHERE (r.created_at, r.id) < (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::uuid)
+AND mp_lower_array(w.category) @> ARRAY[lower(sqlc.arg(category)::text)]
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: CountWorks :one
+SELECT count(*)
+FROM mp_work;
+
+-- name: CountWorksByCategory :one
+SELECT count(*)
+FROM mp_work w
+WHERE mp_lower_array(w.category) @> ARRAY[lower(sqlc.arg(category)::text)];
+
+-- name: ListWorksByContributorCount :many
+-- Works credited to between min_contributors and max_contributors distinct agents
+-- (either bound optional). Works without contributions count as zero.
+SELECT r.id, r.entity_type, r.note, r.created_at, w.category, w.representative_attributes,
+       count(DISTINCT c.agent_id) AS contributor_count
+FROM mp_res r
+JOIN mp_work w ON r.id = w.id
+LEFT JOIN mp_contribution c ON c.work_id = w.id
+WHERE (sqlc.narg(category)::text IS NULL OR mp_lower_array(w.category) @> ARRAY[lower(sqlc.narg(category)::text)])
+GROUP BY r.id, w.id
+HAVING (sqlc.narg(min_contributors)::int IS NULL OR count(DISTINCT c.agent_id) >= sqlc.narg(min_contributors)::int)
+AND (sqlc.narg(max_contributors)::int IS NULL OR count(DISTINCT c.agent_id) <= sqlc.narg(max_contributors)::int)
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: CountWorksByContributorCount :one
+SELECT count(*)
+FROM (
+    SELECT w.id
+    FROM mp_work w
+    LEFT JOIN mp_contribution c ON c.work_id = w.id
+    WHERE (sqlc.narg(category)::text IS NULL OR mp_lower_array(w.category) @> ARRAY[lower(sqlc.narg(category)::text)])
+    GROUP BY w.id
+    HAVING (sqlc.narg(min_contributors)::int IS NULL OR count(DISTINCT c.agent_id) >= sqlc.narg(min_contributors)::int)
+    AND (sqlc.narg(max_contributors)::int IS NULL OR count(DISTINCT c.agent_id) <= sqlc.narg(max_contributors)::int)
+) matched;
+
+-- name: UpsertDraft :exec
+INSERT INTO mp_draft (session_id, kind, data, expires_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (session_id, kind)
+DO UPDATE SET data = EXCLUDED.data, updated_at = now(), expires_at = EXCLUDED.expires_at;
+
+-- name: GetDraft :one
+SELECT data, updated_at, expires_at
+FROM mp_draft
+WHERE session_id = $1 AND kind = $2 AND expires_at > now();
+
+-- name: DeleteDraft :exec
+DELETE FROM mp_draft
+WHERE session_id = $1 AND kind = $2;
+
+-- name: DeleteExpiredDrafts :execrows
+DELETE FROM mp_draft
+WHERE expires_at <= now();
+
+-- name: ListWorkAttrsBatch :many
+-- Walks mp_work in id order for batch processing; pass the last id of the previous batch.
+SELECT w.id, w.representative_attributes
+FROM mp_work w
+WHERE w.id > sqlc.arg(after_id)::uuid
+ORDER BY w.id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ResolveResources :many
+-- Fetches any mix of resources in one round trip. Subtype columns are NULL unless the
+-- resource is of that subtype.
+SELECT
+    r.id, r.entity_type, r.note, r.created_at, r.updated_at,
+    a.contact_info, a.field_of_activity, a.language,
+    p.profession,
+    w.category, w.representative_attributes
+FROM mp_res r
+LEFT JOIN mp_agent a ON r.id = a.id
+LEFT JOIN mp_person p ON r.id = p.id
+LEFT JOIN mp_work w ON r.id = w.id
+WHERE r.id = ANY(sqlc.arg(ids)::uuid[]);
+
+-- name: CreateContribution :one
+INSERT INTO mp_contribution (work_id, agent_id, role)
+VALUES ($1, $2, $3)
+RETURNING id, created_at;
+
+-- name: ListWorkContributors :many
+-- Credits for a work in display order: by role priority from mp_contributor_role,
+-- then alphabetically by role for roles without metadata.
+SELECT
+    c.id, c.agent_id, c.role, r.entity_type,
+    COALESCE(cr.label, c.role)::text AS role_label,
+    cr.priority AS role_priority
+FROM mp_contribution c
+JOIN mp_res r ON c.agent_id = r.id
+LEFT JOIN mp_contributor_role cr ON lower(c.role) = cr.role
+WHERE c.work_id = $1
+ORDER BY cr.priority ASC NULLS LAST, lower(c.role), c.created_at, c.id
+LIMIT $2;
+
+-- name: GetPersonForUpdate :one
+-- Same as GetPerson, but locks the person's root row until the transaction ends.
+SELECT 
+    r.id, r.entity_type, r.note, r.created_at, r.updated_at,
+    a.contact_info, a.field_of_activity, a.language,
+    p.profession
+FROM mp_res r
+JOIN mp_agent a ON r.id = a.id
+JOIN mp_person p ON a.id = p.id
+WHERE r.id = $1
+FOR UPDATE OF r;
+
+-- name: UpdateResNote :exec
+-- Also bumps updated_at through the update_mp_res_modtime trigger.
+UPDATE mp_res SET note = $2
+WHERE id = $1;
+
+-- name: UpdateAgent :exec
+UPDATE mp_agent SET contact_info = $2, field_of_activity = $3, language = $4
+WHERE id = $1;
+
+-- name: UpdatePerson :exec
+UPDATE mp_person SET profession = $2
+WHERE id = $1;
+
+-- name: CountAgentsByLanguage :many
+-- Number of agents per language, most common first.
+SELECT lang::text AS language, count(DISTINCT a.id) AS agent_count
+FROM mp_agent a, unnest(a.language) AS lang
+GROUP BY lang
+ORDER BY agent_count DESC, lang
+LIMIT $1;
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// cachedJSON holds one encoded JSON response for a short time, so that expensive
+// aggregate queries run at most once per TTL however often they are requested.
+type cachedJSON struct {
+	mu        sync.Mutex
+	body      []byte
+	truncated bool
+	expires   time.Time
+}
+
+// serve writes the cached response, calling load to rebuild it when it has expired.
+// The lock is held only while reading or rebuilding the cache, never while writing
+// to the client, so a slow client cannot hold up other requests. Cache-Control tells
+// clients how long the cached copy has left, not the full TTL.
+func (c *cachedJSON) serve(w http.ResponseWriter, ttl time.Duration, load func() (interface{}, pageMeta, error)) {
+	body, truncated, expires, err := c.get(ttl, load)
+	if err != nil {
+		http.Error(w, "Failed to "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if truncated {
+		w.Header().Set(truncatedHeader, "true")
+	}
+	maxAge := max(int(time.Until(expires).Seconds()), 0)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+	w.Write(body)
+}
+
+// get returns the cached response, rebuilding it first if it has expired. Concurrent
+// misses wait on the lock, so only one of them runs load. The body is never modified
+// after it is stored, so it is safe to use once the lock is released.
+func (c *cachedJSON) get(ttl time.Duration, load func() (interface{}, pageMeta, error)) ([]byte, bool, time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Now().After(c.expires) {
+		data, meta, err := load()
+		if err != nil {
+			return nil, false, time.Time{}, fmt.Errorf("load statistics: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(listResponse{Data: data, Meta: meta}); err != nil {
+			return nil, false, time.Time{}, fmt.Errorf("encode response: %w", err)
+		}
+		c.body, c.truncated, c.expires = buf.Bytes(), meta.Truncated, time.Now().Add(ttl)
+	}
+	return c.body, c.truncated, c.expires, nil
+}
+
+// handleLanguageStats returns how many agents work in each language, most common
+// first. Results are cached for STATS_CACHE_TTL.
+func (s *Server) handleLanguageStats(w http.ResponseWriter, r *http.Request) {
+	s.languageStats.serve(w, s.cfg.StatsCacheTTL, func() (interface{}, pageMeta, error) {
+		rows, err := s.queries.CountAgentsByLanguage(r.Context(), s.rowLimit())
+		if err != nil {
+			return nil, pageMeta{}, err
+		}
+		rows, truncated := capRows(w, rows, s.cfg.MaxResultRows)
+		return nonNil(rows), pageMeta{Limit: int32(s.cfg.MaxResultRows), Truncated: truncated}, nil
+	})
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"mangaparty/db"
+)
+
+// parsePageTemplate parses the base layout together with one page template.
+func parsePageTemplate(name string) (*template.Template, error) {
+	return template.ParseFiles("templates/base.html", "templates/"+name)
+}
+
+// templateSamples returns representative data for every page template, matching what
+// the page's handler passes to render. Lists include both a fully populated row and
+// one with every optional field empty, since the empty case is where templates break.
+func templateSamples() map[string]interface{} {
+	id := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+	now := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+	return map[string]interface{}{
+		"index.html":         nil,
+		"person_create.html": nil,
+		"work_create.html":   nil,
+		"person_list.html": []db.ListPeopleRow{
+			{
+				ID:              id,
+				EntityType:      db.MpEntityTypePerson,
+				Note:            []string{"Debuted in 1989"},
+				CreatedAt:       now,
+				UpdatedAt:       now,
+				ContactInfo:     []string{"editor@example.com"},
+				FieldOfActivity: []string{"Manga"},
+				Language:        []string{"Japanese"},
+				Profession:      []string{"Mangaka"},
+			},
+			{ID: id, EntityType: db.MpEntityTypePerson, CreatedAt: now},
+		},
+		"work_list.html": []db.ListWorksRow{
+			{
+				ID:                       id,
+				EntityType:               db.MpEntityTypeWork,
+				Note:                     []string{"Serialized weekly"},
+				CreatedAt:                now,
+				Category:                 []string{"Manga Series"},
+				RepresentativeAttributes: []byte(`{"language":["Japanese"]}`),
+			},
+			{ID: id, EntityType: db.MpEntityTypeWork, CreatedAt: now},
+		},
+	}
+}
+
+// checkTemplates executes every page template in templates/ with its sample data,
+// discarding the output, and reports all failures. Used by the -check-templates flag
+// so that template errors surface in CI instead of on the first page view.
+func checkTemplates() error {
+	pages, err := filepath.Glob("templates/*.html")
+	if err != nil {
+		return err
+	}
+	samples := templateSamples()
+
+	var errs []error
+	for _, path := range pages {
+		name := filepath.Base(path)
+		if name == "base.html" {
+			continue
+		}
+		data, ok := samples[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: no sample data in templateSamples", name))
+			continue
+		}
+		t, err := parsePageTemplate(name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		if err := t.Execute(io.Discard, data); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"mangaparty/db"
+)
+
+// Transaction stages, used to label rollbacks.
+const (
+	txStageBegin  = "begin"
+	txStageStep   = "step"
+	txStageCommit = "commit"
+)
+
+// clientError aborts a transaction because of the request rather than the server,
+// e.g. a missing resource or a failed precondition. It carries the HTTP response.
+type clientError struct {
+	status int
+	msg    string
+}
+
+func (e *clientError) Error() string { return e.msg }
+
+// withTx runs fn inside a transaction, committing if it returns nil and rolling back
+// otherwise. fn must use the ctx it is given for its queries. name identifies the
+// operation in logs. Every rollback is logged at warn level and counted in the
+// tx_rollbacks expvar map under "<stage>.<cause>", where cause is "client" for
+// validation/constraint failures and "server" for the rest.
+func (s *Server) withTx(ctx context.Context, name string, fn func(ctx context.Context, q *db.Queries) error) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		recordRollback(name, txStageBegin, err)
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	// Defer a rollback. If the transaction is committed, this is a no-op.
+	defer tx.Rollback(ctx)
+
+	if err := fn(ctx, s.queries.WithTx(tx)); err != nil {
+		recordRollback(name, txStageStep, err)
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		recordRollback(name, txStageCommit, err)
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+func recordRollback(name, stage string, err error) {
+	cause := rollbackCause(err)
+	txRollbacks.Add(stage+"."+cause, 1)
+	log.Printf("WARN: %s rolled back at %s (%s-caused): %v", name, stage, cause, err)
+}
+
+// rollbackCause classifies why a transaction failed: "client" for errors the request
+// caused (clientError, Postgres data exceptions and constraint violations, or the
+// client going away) and "server" for everything else.
+func rollbackCause(err error) string {
+	var ce *clientError
+	if errors.As(err, &ce) || errors.Is(err, context.Canceled) {
+		return "client"
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case strings.HasPrefix(pgErr.Code, "22"), // data_exception
+			strings.HasPrefix(pgErr.Code, "23"): // integrity_constraint_violation
+			return "client"
+		}
+	}
+	return "server"
+}
+
+// writeTxError reports an error returned by withTx. Step errors are expected to be
+// wrapped with what was being attempted, e.g. "create agent: ...".
+func writeTxError(w http.ResponseWriter, err error) {
+	var ce *clientError
+	if errors.As(err, &ce) {
+		http.Error(w, ce.msg, ce.status)
+		return
+	}
+	status := http.StatusInternalServerError
+	if rollbackCause(err) == "client" {
+		status = http.StatusBadRequest
+	}
+	http.Error(w, "Failed to "+err.Error(), status)
+}